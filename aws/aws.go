@@ -19,54 +19,173 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awscfg "github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type Sessions map[string]aws.Config
 
 type awsCreds struct {
 	credentials []credential
-}
-
-type credential struct {
-	region          string
-	accessKeyId       string
-	secretAccessKey string
+	validate    bool
+	options     sessionOptions
 }
 
 func NewSessions() *awsCreds {
 	return &awsCreds{credentials: []credential{}}
 }
 
+// SetCredential adds a session for region using a static access key pair.
 func (s *awsCreds) SetCredential(region, accessKeyId, secretAccessKey string) *awsCreds {
 	s.credentials = append(s.credentials, credential{
 		region:          region,
-		accessKeyId:       accessKeyId,
+		source:          credentialSourceStatic,
+		accessKeyId:     accessKeyId,
 		secretAccessKey: secretAccessKey,
 	})
 	return s
 }
 
+// SetEnvCredential adds a session for region using the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+func (s *awsCreds) SetEnvCredential(region string) *awsCreds {
+	s.credentials = append(s.credentials, credential{
+		region: region,
+		source: credentialSourceEnv,
+	})
+	return s
+}
+
+// SetProfileCredential adds a session for region using a named profile from
+// the shared config and credentials files, including SSO and source_profile
+// based profiles.
+func (s *awsCreds) SetProfileCredential(region, profile string) *awsCreds {
+	s.credentials = append(s.credentials, credential{
+		region:  region,
+		source:  credentialSourceProfile,
+		profile: profile,
+	})
+	return s
+}
+
+// SetWebIdentityCredential adds a session for region using a web identity
+// token, as mounted by IRSA. Empty roleArn and tokenFile fall back to the
+// AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE environment variables.
+func (s *awsCreds) SetWebIdentityCredential(region, roleArn, tokenFile, sessionName string) *awsCreds {
+	s.credentials = append(s.credentials, credential{
+		region:          region,
+		source:          credentialSourceWebIdentity,
+		roleArn:         roleArn,
+		tokenFile:       tokenFile,
+		roleSessionName: sessionName,
+	})
+	return s
+}
+
+// SetAssumeRoleCredential adds a session for region that assumes role with
+// STS, using the default credential chain as the source identity.
+func (s *awsCreds) SetAssumeRoleCredential(region string, role AssumeRole) *awsCreds {
+	s.credentials = append(s.credentials, credential{
+		region:     region,
+		source:     credentialSourceAssumeRole,
+		assumeRole: role,
+	})
+	return s
+}
+
+// SetDefaultCredential adds a session for region using the SDK default
+// credential chain: environment, shared config, web identity, ECS and EC2
+// instance metadata.
+func (s *awsCreds) SetDefaultCredential(region string) *awsCreds {
+	s.credentials = append(s.credentials, credential{
+		region: region,
+		source: credentialSourceDefault,
+	})
+	return s
+}
+
+// SetValidation makes BuildE call STS GetCallerIdentity with every session
+// to prove its credentials work before returning it.
+func (s *awsCreds) SetValidation(enable bool) *awsCreds {
+	s.validate = enable
+	return s
+}
+
+// Build returns the sessions which could be loaded, dropping failed regions.
+// Use BuildE to find out which regions failed and why.
 func (s *awsCreds) Build() Sessions {
+	sess, _ := s.BuildE(context.Background())
+	return sess
+}
+
+// BuildE returns the sessions which could be loaded, together with a
+// *BuildError listing every region that failed.
+func (s *awsCreds) BuildE(ctx context.Context) (Sessions, error) {
+	return s.build(ctx, s.validate)
+}
+
+// build is BuildE validating the sessions when validate is set, whatever
+// SetValidation set.
+func (s *awsCreds) build(ctx context.Context, validate bool) (Sessions, error) {
 	sess := map[string]aws.Config{}
+	buildErr := &BuildError{}
 	for _, v := range s.credentials {
-		as, err := newAWSSession(v.region, v.accessKeyId, v.secretAccessKey)
+		as, err := newAWSSession(ctx, v, s.options)
+		if err == nil && validate {
+			err = validateSession(ctx, as)
+		}
 		if err != nil {
+			buildErr.Errors = append(buildErr.Errors, &RegionError{Region: v.region, Err: err})
 			continue
 		}
 		sess[v.region] = as
 	}
-	return sess
+	if len(buildErr.Errors) > 0 {
+		return sess, buildErr
+	}
+	return sess, nil
 }
 
-func newAWSSession(region, ak, sk string) (aws.Config, error) {
-	opts := []func(*awscfg.LoadOptions) error{
-		awscfg.WithRegion(region),
+func validateSession(ctx context.Context, cfg aws.Config) error {
+	_, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	return err
+}
+
+func newAWSSession(ctx context.Context, c credential, o sessionOptions) (aws.Config, error) {
+	opts, err := o.loadOptions()
+	if err != nil {
+		return aws.Config{}, err
+	}
+	opts = append(opts, awscfg.WithRegion(c.region))
+
+	switch c.source {
+	case credentialSourceProfile:
+		if err := checkProfile(ctx, c.profile); err != nil {
+			return aws.Config{}, err
+		}
+		opts = append(opts, awscfg.WithSharedConfigProfile(c.profile))
+	case credentialSourceDefault, credentialSourceAssumeRole, credentialSourceWebIdentity:
+	default:
+		provider, err := c.staticProvider()
+		if err != nil {
+			return aws.Config{}, err
+		}
+		opts = append(opts, awscfg.WithCredentialsProvider(provider))
+	}
+
+	cfg, err := awscfg.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	switch c.source {
+	case credentialSourceAssumeRole:
+		cfg.Credentials = aws.NewCredentialsCache(newAssumeRoleProvider(cfg, c.assumeRole))
+	case credentialSourceWebIdentity:
+		provider, err := newWebIdentityProvider(cfg, c)
+		if err != nil {
+			return aws.Config{}, err
+		}
+		cfg.Credentials = aws.NewCredentialsCache(provider)
 	}
-	opts = append(opts, awscfg.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-		ak,
-		sk,
-		"",
-	)))
-	return awscfg.LoadDefaultConfig(context.Background(), opts...)
+	return cfg, nil
 }
@@ -0,0 +1,229 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// setenv sets the environment variables given as key value pairs, an empty
+// value unsetting the variable, and restores them after the spec.
+func setenv(kv ...string) {
+	for i := 0; i < len(kv); i += 2 {
+		key, value := kv[i], kv[i+1]
+		old, ok := os.LookupEnv(key)
+		DeferCleanup(func() {
+			if ok {
+				os.Setenv(key, old)
+			} else {
+				os.Unsetenv(key)
+			}
+		})
+		if value == "" {
+			Expect(os.Unsetenv(key)).To(Succeed())
+		} else {
+			Expect(os.Setenv(key, value)).To(Succeed())
+		}
+	}
+}
+
+// isolateEnv hides the credentials and shared config of the host.
+func isolateEnv() {
+	dir := GinkgoT().TempDir()
+	setenv(
+		"AWS_ACCESS_KEY_ID", "",
+		"AWS_SECRET_ACCESS_KEY", "",
+		"AWS_SESSION_TOKEN", "",
+		"AWS_PROFILE", "",
+		EnvRoleArn, "",
+		EnvWebIdentityTokenFile, "",
+		EnvRoleSessionName, "",
+		"AWS_CONFIG_FILE", filepath.Join(dir, "config"),
+		"AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"),
+		"AWS_EC2_METADATA_DISABLED", "true",
+	)
+}
+
+var _ = Describe("Sessions", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		isolateEnv()
+	})
+
+	It("should build a session per region from static credentials", func() {
+		sess, err := NewSessions().
+			SetCredential("us-east-1", "AKID1", "SECRET1").
+			SetCredential("eu-west-1", "AKID2", "SECRET2").
+			BuildE(ctx)
+		Expect(err).To(BeNil())
+		Expect(sess).To(HaveLen(2))
+		Expect(sess["eu-west-1"].Region).To(Equal("eu-west-1"))
+
+		creds, err := sess["eu-west-1"].Credentials.Retrieve(ctx)
+		Expect(err).To(BeNil())
+		Expect(creds.AccessKeyID).To(Equal("AKID2"))
+		Expect(creds.SecretAccessKey).To(Equal("SECRET2"))
+	})
+
+	It("should keep the last credential set for a region", func() {
+		sess, err := NewSessions().
+			SetCredential("us-east-1", "AKID1", "SECRET1").
+			SetCredential("us-east-1", "AKID2", "SECRET2").
+			BuildE(ctx)
+		Expect(err).To(BeNil())
+		creds, err := sess["us-east-1"].Credentials.Retrieve(ctx)
+		Expect(err).To(BeNil())
+		Expect(creds.AccessKeyID).To(Equal("AKID2"))
+	})
+
+	It("should read the environment credentials", func() {
+		_, err := NewSessions().SetEnvCredential("us-east-1").BuildE(ctx)
+		Expect(err).ToNot(BeNil())
+
+		setenv("AWS_ACCESS_KEY_ID", "AKIDENV", "AWS_SECRET_ACCESS_KEY", "SECRETENV", "AWS_SESSION_TOKEN", "TOKEN")
+		sess, err := NewSessions().SetEnvCredential("us-east-1").BuildE(ctx)
+		Expect(err).To(BeNil())
+		creds, err := sess["us-east-1"].Credentials.Retrieve(ctx)
+		Expect(err).To(BeNil())
+		Expect(creds.AccessKeyID).To(Equal("AKIDENV"))
+		Expect(creds.SessionToken).To(Equal("TOKEN"))
+	})
+
+	It("should read the named profile", func() {
+		Expect(os.WriteFile(os.Getenv("AWS_CONFIG_FILE"), []byte("[profile dba]\nregion = eu-west-1\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), []byte("[default]\naws_access_key_id = AKIDDEFAULT\naws_secret_access_key = SECRET\n\n"+
+			"[dba]\naws_access_key_id = AKIDDBA\naws_secret_access_key = SECRET\n"), 0o600)).To(Succeed())
+
+		sess, err := NewSessions().SetProfileCredential("us-east-1", "dba").BuildE(ctx)
+		Expect(err).To(BeNil())
+		creds, err := sess["us-east-1"].Credentials.Retrieve(ctx)
+		Expect(err).To(BeNil())
+		Expect(creds.AccessKeyID).To(Equal("AKIDDBA"))
+
+	})
+
+	It("should fail the region of a missing profile", func() {
+		Expect(os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"),
+			[]byte("[dba]\naws_access_key_id = AKIDDBA\naws_secret_access_key = SECRET\n"), 0o600)).To(Succeed())
+
+		sess, err := NewSessions().
+			SetProfileCredential("us-east-1", "dba").
+			SetProfileCredential("eu-west-1", "typo").
+			BuildE(ctx)
+		Expect(sess).To(HaveKey("us-east-1"))
+		Expect(sess).ToNot(HaveKey("eu-west-1"))
+
+		var buildErr *BuildError
+		Expect(errors.As(err, &buildErr)).To(BeTrue())
+		Expect(buildErr.Regions()).To(Equal([]string{"eu-west-1"}))
+		var notExist awscfg.SharedConfigProfileNotExistError
+		Expect(errors.As(buildErr.Errors[0].Err, &notExist)).To(BeTrue())
+		Expect(notExist.Profile).To(Equal("typo"))
+	})
+
+	It("should use the default credential chain", func() {
+		setenv("AWS_ACCESS_KEY_ID", "AKIDENV", "AWS_SECRET_ACCESS_KEY", "SECRETENV")
+		sess, err := NewSessions().SetDefaultCredential("us-east-1").BuildE(ctx)
+		Expect(err).To(BeNil())
+		creds, err := sess["us-east-1"].Credentials.Retrieve(ctx)
+		Expect(err).To(BeNil())
+		Expect(creds.AccessKeyID).To(Equal("AKIDENV"))
+	})
+
+	It("should require a role and a token file for web identity", func() {
+		_, err := NewSessions().SetWebIdentityCredential("us-east-1", "", "", "").BuildE(ctx)
+		Expect(err).To(MatchError(ContainSubstring("web identity requires role arn and token file")))
+
+		setenv(EnvRoleArn, "arn:aws:iam::123456789012:role/dba", EnvWebIdentityTokenFile, "/var/run/secrets/token")
+		sess, err := NewSessions().SetWebIdentityCredential("us-east-1", "", "", "").BuildE(ctx)
+		Expect(err).To(BeNil())
+		Expect(sess).To(HaveKey("us-east-1"))
+	})
+
+	It("should assume the role with the default credentials", func() {
+		var form url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			Expect(req.ParseForm()).To(Succeed())
+			form = req.PostForm
+			w.Header().Set("Content-Type", "text/xml")
+			_, _ = w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>` +
+				`<AccessKeyId>ASIAROLE</AccessKeyId><SecretAccessKey>SECRETROLE</SecretAccessKey>` +
+				`<SessionToken>TOKENROLE</SessionToken><Expiration>2100-01-01T00:00:00Z</Expiration>` +
+				`</Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+		}))
+		defer server.Close()
+
+		setenv("AWS_ACCESS_KEY_ID", "AKIDENV", "AWS_SECRET_ACCESS_KEY", "SECRETENV")
+		sess, err := NewSessions().
+			SetAssumeRoleCredential("us-east-1", AssumeRole{RoleArn: "arn:aws:iam::123456789012:role/dba", ExternalID: "mesh"}).
+			SetEndpoint(AllServices, server.URL).
+			BuildE(ctx)
+		Expect(err).To(BeNil())
+		creds, err := sess["us-east-1"].Credentials.Retrieve(ctx)
+		Expect(err).To(BeNil())
+		Expect(creds.AccessKeyID).To(Equal("ASIAROLE"))
+		Expect(form.Get("Action")).To(Equal("AssumeRole"))
+		Expect(form.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/dba"))
+		Expect(form.Get("RoleSessionName")).To(Equal(DefaultRoleSessionName))
+		Expect(form.Get("ExternalId")).To(Equal("mesh"))
+	})
+
+	It("should list every failed region in the build error", func() {
+		creds := NewSessions().
+			SetCredential("us-east-1", "AKID", "SECRET").
+			SetEnvCredential("eu-west-1").
+			SetWebIdentityCredential("ap-south-1", "", "", "")
+		sess, err := creds.BuildE(ctx)
+		Expect(sess).To(HaveLen(1))
+		Expect(sess).To(HaveKey("us-east-1"))
+		Expect(creds.Build()).To(HaveLen(1))
+
+		var buildErr *BuildError
+		Expect(errors.As(err, &buildErr)).To(BeTrue())
+		Expect(buildErr.Regions()).To(Equal([]string{"eu-west-1", "ap-south-1"}))
+		Expect(buildErr.Unwrap()).To(HaveLen(2))
+		Expect(err.Error()).To(ContainSubstring("failed to build 2 aws session(s): region eu-west-1: "))
+
+		var regionErr *RegionError
+		Expect(errors.As(buildErr.Unwrap()[1], &regionErr)).To(BeTrue())
+		Expect(regionErr.Region).To(Equal("ap-south-1"))
+		Expect(errors.Unwrap(regionErr)).To(Equal(regionErr.Err))
+	})
+
+	It("should fail every region on invalid options", func() {
+		_, err := NewSessions().
+			SetCredential("us-east-1", "AKID", "SECRET").
+			SetCredential("eu-west-1", "AKID", "SECRET").
+			SetProxy("://proxy").
+			BuildE(ctx)
+		var buildErr *BuildError
+		Expect(errors.As(err, &buildErr)).To(BeTrue())
+		Expect(buildErr.Regions()).To(Equal([]string{"us-east-1", "eu-west-1"}))
+		Expect(err.Error()).To(ContainSubstring("invalid proxy url"))
+	})
+})
@@ -18,7 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/aws/smithy-go"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -42,24 +42,69 @@ type Aurora interface {
 	SetSourceDBClusterIdentifier(id string) Aurora
 	SetRestoreToTime(t time.Time) Aurora
 	SetRestoreType(t DBClusterRestoreType) Aurora
+	SetBackupRetentionPeriod(days int32) Aurora
+	SetPreferredMaintenanceWindow(window string) Aurora
+	SetDeletionProtection(enable bool) Aurora
+	SetDBClusterParameterGroupName(name string) Aurora
+	SetApplyImmediately(enable bool) Aurora
+	SetEndpointIdentifier(id string) Aurora
+	SetEndpointType(t CustomEndpointType) Aurora
+	SetStaticMembers(ids []string) Aurora
+	SetExcludedMembers(ids []string) Aurora
+	SetReaderFailoverMode(mode ReaderFailoverMode) Aurora
+	SetReaderAvailabilityZone(az string) Aurora
+	SetReaderTag(key, value string) Aurora
 
 	// RDSInstance for Aurora
 	SetDBInstanceIdentifier(id string) Aurora
 	SetDBInstanceClass(class string) Aurora
 	SetPublicAccessible(enable bool) Aurora
 	SetDeleteAutomateBackups(enable bool) Aurora
+	SetDBParameterGroupName(name string) Aurora
+
+	SetWatchInterval(interval time.Duration) Aurora
+	SetFilter(name string, values []string) Aurora
+	SetSnapshotFilter(name string, values []string) Aurora
+	SetMaxResults(max int32) Aurora
+	SetTargetSnapshotIdentifier(id string) Aurora
+	SetCopyTags(enable bool) Aurora
+	SetKmsKeyId(id string) Aurora
+	SetSourceRegion(region string) Aurora
+	SetTags(tags map[string]string) Aurora
+	SetGlobalClusterIdentifier(id string) Aurora
+	SetServerlessV2Scaling(min, max float64) Aurora
 
 	Create(context.Context) error
 	CreateWithPrimary(context.Context) error
 	FailoverPrimary(context.Context) error
-	FailoverRandomOneReadonlyEndpoint(context.Context) error
+	FailoverRandomOneReadonlyEndpoint(context.Context) (*DescInstance, error)
 	NewReadonlyEndpoint(context.Context) error
+	ModifyReadonlyEndpoint(context.Context) error
+	DescribeReadonlyEndpoints(context.Context) ([]CustomEndpoint, error)
+	DeleteReadonlyEndpoint(context.Context) error
 	Delete(context.Context) error
+	Modify(context.Context) error
+	Start(context.Context) error
+	Stop(context.Context) error
 	Describe(context.Context) (*DescCluster, error)
+	List(context.Context) ([]*DescCluster, error)
+	Iterate(ctx context.Context, fn func(*DescCluster) bool) error
+	ListSnapshots(context.Context) ([]*DescClusterSnapshot, error)
+	IterateSnapshots(ctx context.Context, fn func(*DescClusterSnapshot) bool) error
 	CreateSnapshot(context.Context) error
 	DescribeSnapshot(context.Context) (*DescClusterSnapshot, error)
+	DeleteSnapshot(context.Context) error
+	CopySnapshot(context.Context) error
+	WaitForSnapshot(ctx context.Context, opts *WaitOptions) error
+	ShareSnapshot(ctx context.Context, accounts ...string) error
+	UnshareSnapshot(ctx context.Context, accounts ...string) error
 	RestoreFromSnapshot(context.Context) error
 	RestoreToPitr(ctx context.Context) error
+	WaitFor(ctx context.Context, status DBClusterStatus, opts *WaitOptions) error
+	Watch(ctx context.Context) <-chan StatusEvent
+	Events(ctx context.Context, sourceID string, since time.Time) ([]*Event, error)
+	ListLogFiles(context.Context) ([]LogFile, error)
+	StreamLogFile(ctx context.Context, file LogFile, opts *LogStreamOptions) (io.ReadCloser, error)
 }
 
 type rdsAurora struct {
@@ -77,16 +122,61 @@ type rdsAurora struct {
 	describeClusterSnapshotParam    *rds.DescribeDBClusterSnapshotsInput
 	restoreClusterFromSnapshotParam *rds.RestoreDBClusterFromSnapshotInput
 	restoreClusterPitrParam         *rds.RestoreDBClusterToPointInTimeInput
+	modifyClusterParam              *rds.ModifyDBClusterInput
+	startClusterParam               *rds.StartDBClusterInput
+	stopClusterParam                *rds.StopDBClusterInput
+	deleteClusterSnapshotParam      *rds.DeleteDBClusterSnapshotInput
+	copyClusterSnapshotParam        *rds.CopyDBClusterSnapshotInput
+	createEndpointParam             *rds.CreateDBClusterEndpointInput
+	modifyEndpointParam             *rds.ModifyDBClusterEndpointInput
+	deleteEndpointParam             *rds.DeleteDBClusterEndpointInput
 
 	createInstanceParam      *rds.CreateDBInstanceInput
 	deleteInstanceParam      *rds.DeleteDBInstanceInput
 	rebootInstanceParam      *rds.RebootDBInstanceInput
 	describeInstanceParam    *rds.DescribeDBInstancesInput
 	restoreInstancePitrParam *rds.RestoreDBInstanceToPointInTimeInput
+	modifyInstanceParam      *rds.ModifyDBInstanceInput
+
+	readerFailover readerFailover
+
+	maxResults    int32
+	tagFilters    []types.Filter
+	watchInterval time.Duration
 }
 
 var _ Aurora = &rdsAurora{}
 
+func newAurora(core *rds.Client) *rdsAurora {
+	return &rdsAurora{
+		core:                            core,
+		createClusterParam:              &rds.CreateDBClusterInput{},
+		deleteClusterParam:              &rds.DeleteDBClusterInput{},
+		failoverClusterParam:            &rds.FailoverDBClusterInput{},
+		failoverGlobalClusterParam:      &rds.FailoverGlobalClusterInput{},
+		rebootClusterParam:              &rds.RebootDBClusterInput{},
+		describeClusterParam:            &rds.DescribeDBClustersInput{},
+		restoreClusterPitrParam:         &rds.RestoreDBClusterToPointInTimeInput{},
+		createInstanceParam:             &rds.CreateDBInstanceInput{},
+		deleteInstanceParam:             &rds.DeleteDBInstanceInput{},
+		rebootInstanceParam:             &rds.RebootDBInstanceInput{},
+		describeInstanceParam:           &rds.DescribeDBInstancesInput{},
+		restoreInstancePitrParam:        &rds.RestoreDBInstanceToPointInTimeInput{},
+		createClusterSnapshotParam:      &rds.CreateDBClusterSnapshotInput{},
+		describeClusterSnapshotParam:    &rds.DescribeDBClusterSnapshotsInput{},
+		restoreClusterFromSnapshotParam: &rds.RestoreDBClusterFromSnapshotInput{},
+		modifyClusterParam:              &rds.ModifyDBClusterInput{},
+		startClusterParam:               &rds.StartDBClusterInput{},
+		stopClusterParam:                &rds.StopDBClusterInput{},
+		deleteClusterSnapshotParam:      &rds.DeleteDBClusterSnapshotInput{},
+		copyClusterSnapshotParam:        &rds.CopyDBClusterSnapshotInput{},
+		createEndpointParam:             &rds.CreateDBClusterEndpointInput{EndpointType: aws.String(string(CustomEndpointTypeReader))},
+		modifyEndpointParam:             &rds.ModifyDBClusterEndpointInput{},
+		deleteEndpointParam:             &rds.DeleteDBClusterEndpointInput{},
+		modifyInstanceParam:             &rds.ModifyDBInstanceInput{},
+	}
+}
+
 func (s *rdsAurora) SetEngine(engine string) Aurora {
 	s.createClusterParam.Engine = aws.String(engine)
 	s.createInstanceParam.Engine = aws.String(engine)
@@ -110,6 +200,10 @@ func (s *rdsAurora) SetDBClusterIdentifier(id string) Aurora {
 	s.describeClusterSnapshotParam.DBClusterIdentifier = aws.String(id)
 	s.restoreClusterFromSnapshotParam.DBClusterIdentifier = aws.String(id)
 	s.restoreClusterPitrParam.DBClusterIdentifier = aws.String(id)
+	s.modifyClusterParam.DBClusterIdentifier = aws.String(id)
+	s.startClusterParam.DBClusterIdentifier = aws.String(id)
+	s.stopClusterParam.DBClusterIdentifier = aws.String(id)
+	s.createEndpointParam.DBClusterIdentifier = aws.String(id)
 	return s
 }
 
@@ -140,6 +234,7 @@ func (s *rdsAurora) SetDBInstanceIdentifier(id string) Aurora {
 
 func (s *rdsAurora) SetDBInstanceClass(class string) Aurora {
 	s.createInstanceParam.DBInstanceClass = aws.String(class)
+	s.modifyInstanceParam.DBInstanceClass = aws.String(class)
 	return s
 }
 
@@ -158,6 +253,7 @@ func (s *rdsAurora) SetMasterUsername(user string) Aurora {
 
 func (s *rdsAurora) SetMasterUserPassword(pass string) Aurora {
 	s.createClusterParam.MasterUserPassword = aws.String(pass)
+	s.modifyClusterParam.MasterUserPassword = aws.String(pass)
 	return s
 }
 
@@ -172,6 +268,13 @@ func (s *rdsAurora) SetDeleteAutomateBackups(enable bool) Aurora {
 	return s
 }
 
+// SetDBParameterGroupName sets the parameter group of the instances of the cluster.
+func (s *rdsAurora) SetDBParameterGroupName(name string) Aurora {
+	s.createInstanceParam.DBParameterGroupName = aws.String(name)
+	s.modifyInstanceParam.DBParameterGroupName = aws.String(name)
+	return s
+}
+
 func (s *rdsAurora) SetDBName(name string) Aurora {
 	s.createClusterParam.DatabaseName = aws.String(name)
 	s.restoreInstancePitrParam.DBName = aws.String(name)
@@ -181,6 +284,8 @@ func (s *rdsAurora) SetDBName(name string) Aurora {
 func (s *rdsAurora) SetSnapshotIdentifier(id string) Aurora {
 	s.createClusterSnapshotParam.DBClusterSnapshotIdentifier = aws.String(id)
 	s.describeClusterSnapshotParam.DBClusterSnapshotIdentifier = aws.String(id)
+	s.deleteClusterSnapshotParam.DBClusterSnapshotIdentifier = aws.String(id)
+	s.copyClusterSnapshotParam.SourceDBClusterSnapshotIdentifier = aws.String(id)
 	s.restoreClusterFromSnapshotParam.SnapshotIdentifier = aws.String(id)
 	return s
 }
@@ -202,13 +307,46 @@ func (s *rdsAurora) SetRestoreType(t DBClusterRestoreType) Aurora {
 	return s
 }
 
+func (s *rdsAurora) SetBackupRetentionPeriod(days int32) Aurora {
+	s.createClusterParam.BackupRetentionPeriod = aws.Int32(days)
+	s.modifyClusterParam.BackupRetentionPeriod = aws.Int32(days)
+	return s
+}
+
+// SetPreferredMaintenanceWindow sets the weekly maintenance window in UTC, formatted ddd:hh24:mi-ddd:hh24:mi.
+func (s *rdsAurora) SetPreferredMaintenanceWindow(window string) Aurora {
+	s.createClusterParam.PreferredMaintenanceWindow = aws.String(window)
+	s.modifyClusterParam.PreferredMaintenanceWindow = aws.String(window)
+	return s
+}
+
+func (s *rdsAurora) SetDeletionProtection(enable bool) Aurora {
+	s.createClusterParam.DeletionProtection = aws.Bool(enable)
+	s.restoreClusterFromSnapshotParam.DeletionProtection = aws.Bool(enable)
+	s.restoreClusterPitrParam.DeletionProtection = aws.Bool(enable)
+	s.modifyClusterParam.DeletionProtection = aws.Bool(enable)
+	return s
+}
+
+func (s *rdsAurora) SetDBClusterParameterGroupName(name string) Aurora {
+	s.createClusterParam.DBClusterParameterGroupName = aws.String(name)
+	s.restoreClusterFromSnapshotParam.DBClusterParameterGroupName = aws.String(name)
+	s.restoreClusterPitrParam.DBClusterParameterGroupName = aws.String(name)
+	s.modifyClusterParam.DBClusterParameterGroupName = aws.String(name)
+	return s
+}
+
+// SetApplyImmediately applies Modify now instead of during the next maintenance window.
+func (s *rdsAurora) SetApplyImmediately(enable bool) Aurora {
+	s.modifyClusterParam.ApplyImmediately = enable
+	s.modifyInstanceParam.ApplyImmediately = enable
+	return s
+}
+
 func (s *rdsAurora) CreateSnapshot(ctx context.Context) error {
 	snapshot, err := s.DescribeSnapshot(ctx)
-
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBClusterSnapshotNotFoundFault); !ok {
-			return err
-		}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
 	}
 
 	if snapshot != nil {
@@ -216,46 +354,56 @@ func (s *rdsAurora) CreateSnapshot(ctx context.Context) error {
 	}
 
 	_, err = s.core.CreateDBClusterSnapshot(ctx, s.createClusterSnapshotParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsAurora) Create(ctx context.Context) error {
-	if _, err := s.core.CreateDBCluster(ctx, s.createClusterParam); err != nil {
+	if err := s.validateServerlessV2(); err != nil {
 		return err
 	}
+	if _, err := s.core.CreateDBCluster(ctx, s.createClusterParam); err != nil {
+		return wrapError(err)
+	}
+
+	return s.createInstances(ctx)
+}
 
+func (s *rdsAurora) validateServerlessV2() error {
+	p := s.createClusterParam
+	return validateServerlessV2Create(p.Engine, p.EngineVersion, p.EngineMode, p.ServerlessV2ScalingConfiguration, s.createInstanceParam.DBInstanceClass)
+}
+
+// createInstances creates instanceNumber instances named <cluster>-instance-<n>,
+// each from its own copy of the create instance input.
+func (s *rdsAurora) createInstances(ctx context.Context) error {
+	clusterId := aws.ToString(s.createClusterParam.DBClusterIdentifier)
 	for i := 1; i <= int(s.instanceNumber); i++ {
-		instanceIdentifierName := fmt.Sprintf("%s-instance-%d", *s.createClusterParam.DBClusterIdentifier, i)
-		s.SetDBInstanceIdentifier(instanceIdentifierName)
-		if _, err := s.core.CreateDBInstance(ctx, s.createInstanceParam); err != nil {
-			return err
+		param := *s.createInstanceParam
+		param.DBInstanceIdentifier = aws.String(fmt.Sprintf("%s-instance-%d", clusterId, i))
+		if _, err := s.core.CreateDBInstance(ctx, &param); err != nil {
+			return wrapError(err)
 		}
 	}
 	return nil
 }
 
 func (s *rdsAurora) CreateWithPrimary(ctx context.Context) error {
-	if _, err := s.core.CreateDBCluster(ctx, s.createClusterParam); err != nil {
+	if err := s.validateServerlessV2(); err != nil {
 		return err
 	}
+	if _, err := s.core.CreateDBCluster(ctx, s.createClusterParam); err != nil {
+		return wrapError(err)
+	}
 
 	if _, err := s.core.CreateDBInstance(ctx, s.createInstanceParam); err != nil {
-		return err
+		return wrapError(err)
 	}
 	return nil
 }
 
-func (s *rdsAurora) NewReadonlyEndpoint(ctx context.Context) error {
-	return nil
-}
-
 func (s *rdsAurora) FailoverPrimary(ctx context.Context) error {
 	_, err := s.core.FailoverDBCluster(ctx, s.failoverClusterParam)
-	return err
-}
-
-func (s *rdsAurora) FailoverRandomOneReadonlyEndpoint(ctx context.Context) error {
-	return nil
+	return wrapError(err)
 }
 
 func (s *rdsAurora) Delete(ctx context.Context) error {
@@ -264,36 +412,22 @@ func (s *rdsAurora) Delete(ctx context.Context) error {
 	}
 
 	// delete instances of cluster
-	var exists = false
-	for _, filter := range s.describeClusterParam.Filters {
-		if *filter.Name == "db-cluster-id" {
-			filter.Values = []string{*s.createClusterParam.DBClusterIdentifier}
-			exists = true
-			break
-		}
-	}
-	if !exists {
-		s.describeInstanceParam.Filters = append(s.describeInstanceParam.Filters, types.Filter{
-			Name:   aws.String("db-cluster-id"),
-			Values: []string{*s.createClusterParam.DBClusterIdentifier},
-		})
-	}
-
-	instances, err := s.core.DescribeDBInstances(ctx, s.describeInstanceParam)
+	instances, err := s.describeInstances(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, instance := range instances.DBInstances {
-		s.deleteInstanceParam.DBInstanceIdentifier = instance.DBInstanceIdentifier
-		if _, err := s.core.DeleteDBInstance(ctx, s.deleteInstanceParam); err != nil {
-			return err
+	for _, instance := range instances {
+		param := *s.deleteInstanceParam
+		param.DBInstanceIdentifier = aws.String(instance.DBInstanceIdentifier)
+		if _, err := s.core.DeleteDBInstance(ctx, &param); err != nil {
+			return wrapError(err)
 		}
 	}
 
 	// delete cluster
 	if _, err := s.core.DeleteDBCluster(ctx, s.deleteClusterParam); err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBClusterNotFoundFault); !ok {
+		if err = wrapError(err); !errors.Is(err, ErrNotFound) {
 			return err
 		}
 	}
@@ -301,23 +435,87 @@ func (s *rdsAurora) Delete(ctx context.Context) error {
 	return nil
 }
 
+// Modify changes the cluster, then every instance of the cluster when
+// SetDBInstanceClass or SetDBParameterGroupName is set.
+func (s *rdsAurora) Modify(ctx context.Context) error {
+	if err := validateServerlessV2Modify(s.modifyClusterParam.ServerlessV2ScalingConfiguration); err != nil {
+		return err
+	}
+	if _, err := s.core.ModifyDBCluster(ctx, s.modifyClusterParam); err != nil {
+		return wrapError(err)
+	}
+
+	if s.modifyInstanceParam.DBInstanceClass == nil && s.modifyInstanceParam.DBParameterGroupName == nil {
+		return nil
+	}
+
+	instances, err := s.describeInstances(ctx)
+	if err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		param := *s.modifyInstanceParam
+		param.DBInstanceIdentifier = aws.String(instance.DBInstanceIdentifier)
+		if _, err := s.core.ModifyDBInstance(ctx, &param); err != nil {
+			return wrapError(err)
+		}
+	}
+	return nil
+}
+
+// Start starts the cluster together with its instances.
+func (s *rdsAurora) Start(ctx context.Context) error {
+	_, err := s.core.StartDBCluster(ctx, s.startClusterParam)
+	return wrapError(err)
+}
+
+// Stop stops the cluster together with its instances, which rds starts again
+// by itself after seven days.
+func (s *rdsAurora) Stop(ctx context.Context) error {
+	_, err := s.core.StopDBCluster(ctx, s.stopClusterParam)
+	return wrapError(err)
+}
+
 func (s *rdsAurora) Describe(ctx context.Context) (*DescCluster, error) {
 	out, err := s.core.DescribeDBClusters(ctx, s.describeClusterParam)
 	// if cluster not found, aws api will return error.
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBClusterNotFoundFault); ok {
+	if err = wrapError(err); err != nil {
+		if errors.Is(err, ErrNotFound) {
 			return nil, nil
 		}
 		return nil, err
 	}
+	if len(out.DBClusters) == 0 {
+		return nil, nil
+	}
+
+	desc := convertDBCluster(&out.DBClusters[0])
+	if err := describeEndpoints(ctx, s.core, desc); err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
 
-	return convertDBCluster(&out.DBClusters[0]), nil
+// describeInstances returns the instances of the cluster.
+func (s *rdsAurora) describeInstances(ctx context.Context) ([]*DescInstance, error) {
+	param := &rds.DescribeDBInstancesInput{
+		Filters: setFilter(nil, "db-cluster-id", []string{aws.ToString(s.describeClusterParam.DBClusterIdentifier)}),
+	}
+	output, err := s.core.DescribeDBInstances(ctx, param)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	descs := make([]*DescInstance, 0, len(output.DBInstances))
+	for i := range output.DBInstances {
+		descs = append(descs, convertDBInstance(&output.DBInstances[i]))
+	}
+	return descs, nil
 }
 
 func (s *rdsAurora) DescribeSnapshot(ctx context.Context) (*DescClusterSnapshot, error) {
 	snapshots, err := s.core.DescribeDBClusterSnapshots(ctx, s.describeClusterSnapshotParam)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 	if len(snapshots.DBClusterSnapshots) == 0 {
 		return nil, nil
@@ -329,33 +527,17 @@ func (s *rdsAurora) DescribeSnapshot(ctx context.Context) (*DescClusterSnapshot,
 func (s *rdsAurora) RestoreFromSnapshot(ctx context.Context) error {
 	_, err := s.core.RestoreDBClusterFromSnapshot(ctx, s.restoreClusterFromSnapshotParam)
 	if err != nil {
-		return err
-	}
-
-	for i := 1; i <= int(s.instanceNumber); i++ {
-		instanceIdentifierName := fmt.Sprintf("%s-instance-%d", *s.createClusterParam.DBClusterIdentifier, i)
-		s.SetDBInstanceIdentifier(instanceIdentifierName)
-		if _, err = s.core.CreateDBInstance(ctx, s.createInstanceParam); err != nil {
-			return err
-		}
+		return wrapError(err)
 	}
 
-	return nil
+	return s.createInstances(ctx)
 }
 
 func (s *rdsAurora) RestoreToPitr(ctx context.Context) error {
 	_, err := s.core.RestoreDBClusterToPointInTime(ctx, s.restoreClusterPitrParam)
 	if err != nil {
-		return err
+		return wrapError(err)
 	}
 
-	for i := 1; i <= int(s.instanceNumber); i++ {
-		instanceIdentifierName := fmt.Sprintf("%s-instance-%d", *s.createClusterParam.DBClusterIdentifier, i)
-		s.SetDBInstanceIdentifier(instanceIdentifierName)
-		if _, err = s.core.CreateDBInstance(ctx, s.createInstanceParam); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return s.createInstances(ctx)
 }
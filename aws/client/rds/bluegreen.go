@@ -0,0 +1,446 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	BlueGreenStatusProvisioning         = "PROVISIONING"
+	BlueGreenStatusAvailable            = "AVAILABLE"
+	BlueGreenStatusSwitchoverInProgress = "SWITCHOVER_IN_PROGRESS"
+	BlueGreenStatusSwitchoverCompleted  = "SWITCHOVER_COMPLETED"
+	BlueGreenStatusSwitchoverFailed     = "SWITCHOVER_FAILED"
+	BlueGreenStatusInvalidConfiguration = "INVALID_CONFIGURATION"
+	BlueGreenStatusProvisioningFailed   = "PROVISIONING_FAILED"
+	BlueGreenStatusDeleting             = "DELETING"
+
+	parameterApplyStatusInSync  = "in-sync"
+	blueGreenNameFilter         = "blue-green-deployment-name"
+	logicalReplicationParameter = "rds.logical_replication"
+)
+
+// ErrPreflight is matched by the *PreflightError returned by Create when the
+// source does not meet the prerequisites of a blue/green deployment.
+var ErrPreflight = errors.New("blue/green deployment preflight failed")
+
+type DescBlueGreenDeployment struct {
+	BlueGreenDeploymentIdentifier string
+	BlueGreenDeploymentName       string
+	Source                        string
+	Target                        string
+	Status                        string
+	StatusDetails                 string
+	SwitchoverDetails             []SwitchoverDetail
+	Tasks                         []BlueGreenTask
+	CreateTime                    time.Time
+	DeleteTime                    time.Time
+	Tags                          map[string]string
+}
+
+// SwitchoverDetail is the switchover of a blue resource to its green resource.
+type SwitchoverDetail struct {
+	SourceMember string
+	TargetMember string
+	Status       string
+}
+
+type BlueGreenTask struct {
+	Name   string
+	Status string
+}
+
+// PreflightIssue is a prerequisite of blue/green deployments the source misses.
+type PreflightIssue struct {
+	Check   string
+	Message string
+}
+
+type PreflightError struct {
+	Source string
+	Issues []PreflightIssue
+}
+
+func (e *PreflightError) Error() string {
+	msgs := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		msgs = append(msgs, issue.Check+": "+issue.Message)
+	}
+	return fmt.Sprintf("%s of %s: %s", ErrPreflight, e.Source, strings.Join(msgs, "; "))
+}
+
+func (e *PreflightError) Is(target error) bool {
+	return target == ErrPreflight
+}
+
+// BlueGreen manages the blue/green deployments of an instance or a cluster,
+// which copy the source to a green environment kept in sync by logical
+// replication, upgraded or modified, then switched over with minimal downtime.
+type BlueGreen interface {
+	SetBlueGreenDeploymentIdentifier(id string) BlueGreen
+	SetBlueGreenDeploymentName(name string) BlueGreen
+	SetSource(arn string) BlueGreen
+	SetTargetEngineVersion(version string) BlueGreen
+	SetTargetDBParameterGroupName(name string) BlueGreen
+	SetTargetDBClusterParameterGroupName(name string) BlueGreen
+	SetTags(tags map[string]string) BlueGreen
+	SetSwitchoverTimeout(timeout time.Duration) BlueGreen
+	SetDeleteTarget(enable bool) BlueGreen
+	SetSkipPreflight(skip bool) BlueGreen
+
+	// Preflight checks the source set by SetSource before Create.
+	Preflight(context.Context) ([]PreflightIssue, error)
+	// Create runs Preflight unless skipped, then creates the deployment.
+	Create(context.Context) (*DescBlueGreenDeployment, error)
+	// Describe returns the deployment of the identifier, or else of the name,
+	// nil when it does not exist.
+	Describe(context.Context) (*DescBlueGreenDeployment, error)
+	Switchover(context.Context) error
+	Delete(context.Context) error
+	WaitFor(ctx context.Context, status string, opts *WaitOptions) error
+	// WaitForSwitchover waits until the switchover completes.
+	WaitForSwitchover(ctx context.Context, opts *WaitOptions) error
+}
+
+type rdsBlueGreen struct {
+	core *rds.Client
+
+	createBlueGreenParam     *rds.CreateBlueGreenDeploymentInput
+	describeBlueGreenParam   *rds.DescribeBlueGreenDeploymentsInput
+	switchoverBlueGreenParam *rds.SwitchoverBlueGreenDeploymentInput
+	deleteBlueGreenParam     *rds.DeleteBlueGreenDeploymentInput
+
+	skipPreflight bool
+}
+
+func newBlueGreen(core *rds.Client) *rdsBlueGreen {
+	return &rdsBlueGreen{
+		core:                     core,
+		createBlueGreenParam:     &rds.CreateBlueGreenDeploymentInput{},
+		describeBlueGreenParam:   &rds.DescribeBlueGreenDeploymentsInput{},
+		switchoverBlueGreenParam: &rds.SwitchoverBlueGreenDeploymentInput{},
+		deleteBlueGreenParam:     &rds.DeleteBlueGreenDeploymentInput{},
+	}
+}
+
+func (s *rdsBlueGreen) SetBlueGreenDeploymentIdentifier(id string) BlueGreen {
+	s.describeBlueGreenParam.BlueGreenDeploymentIdentifier = aws.String(id)
+	s.switchoverBlueGreenParam.BlueGreenDeploymentIdentifier = aws.String(id)
+	s.deleteBlueGreenParam.BlueGreenDeploymentIdentifier = aws.String(id)
+	return s
+}
+
+func (s *rdsBlueGreen) SetBlueGreenDeploymentName(name string) BlueGreen {
+	s.createBlueGreenParam.BlueGreenDeploymentName = aws.String(name)
+	s.describeBlueGreenParam.Filters = setFilter(s.describeBlueGreenParam.Filters, blueGreenNameFilter, []string{name})
+	return s
+}
+
+// SetSource sets the ARN of the blue instance or cluster.
+func (s *rdsBlueGreen) SetSource(arn string) BlueGreen {
+	s.createBlueGreenParam.Source = aws.String(arn)
+	return s
+}
+
+func (s *rdsBlueGreen) SetTargetEngineVersion(version string) BlueGreen {
+	s.createBlueGreenParam.TargetEngineVersion = aws.String(version)
+	return s
+}
+
+func (s *rdsBlueGreen) SetTargetDBParameterGroupName(name string) BlueGreen {
+	s.createBlueGreenParam.TargetDBParameterGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsBlueGreen) SetTargetDBClusterParameterGroupName(name string) BlueGreen {
+	s.createBlueGreenParam.TargetDBClusterParameterGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsBlueGreen) SetTags(tags map[string]string) BlueGreen {
+	s.createBlueGreenParam.Tags = toTags(tags)
+	return s
+}
+
+// SetSwitchoverTimeout bounds the switchover, which is rolled back when it
+// takes longer. Rounded down to seconds.
+func (s *rdsBlueGreen) SetSwitchoverTimeout(timeout time.Duration) BlueGreen {
+	s.switchoverBlueGreenParam.SwitchoverTimeout = aws.Int32(int32(timeout / time.Second))
+	return s
+}
+
+// SetDeleteTarget makes Delete delete the green resources as well, which is
+// only allowed before the switchover.
+func (s *rdsBlueGreen) SetDeleteTarget(enable bool) BlueGreen {
+	s.deleteBlueGreenParam.DeleteTarget = aws.Bool(enable)
+	return s
+}
+
+func (s *rdsBlueGreen) SetSkipPreflight(skip bool) BlueGreen {
+	s.skipPreflight = skip
+	return s
+}
+
+func (s *rdsBlueGreen) Preflight(ctx context.Context) ([]PreflightIssue, error) {
+	source := aws.ToString(s.createBlueGreenParam.Source)
+	if source == "" {
+		return nil, errors.New("blue/green deployment source is required")
+	}
+
+	if strings.Contains(source, ":cluster:") {
+		descs, err := newCluster(s.core).SetFilter("db-cluster-id", []string{source}).SetMaxResults(1).List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(descs) == 0 {
+			return nil, fmt.Errorf("db cluster %s: %w", source, ErrNotFound)
+		}
+		desc := descs[0]
+		params, err := newParameterGroup(s.core).SetName(desc.DBClusterParameterGroup).SetCluster(true).GetParameters(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return checkBlueGreenCluster(desc, params), nil
+	}
+
+	descs, err := newInstance(s.core).SetFilter("db-instance-id", []string{source}).SetMaxResults(1).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(descs) == 0 {
+		return nil, fmt.Errorf("db instance %s: %w", source, ErrNotFound)
+	}
+	desc := descs[0]
+	var params []Parameter
+	if len(desc.DBParameterGroups) > 0 {
+		params, err = newParameterGroup(s.core).SetName(desc.DBParameterGroups[0].Name).GetParameters(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return checkBlueGreenInstance(desc, params), nil
+}
+
+func (s *rdsBlueGreen) Create(ctx context.Context) (*DescBlueGreenDeployment, error) {
+	if !s.skipPreflight {
+		issues, err := s.Preflight(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			return nil, &PreflightError{Source: aws.ToString(s.createBlueGreenParam.Source), Issues: issues}
+		}
+	}
+
+	out, err := s.core.CreateBlueGreenDeployment(ctx, s.createBlueGreenParam)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return convertBlueGreenDeployment(out.BlueGreenDeployment), nil
+}
+
+func (s *rdsBlueGreen) Describe(ctx context.Context) (*DescBlueGreenDeployment, error) {
+	if s.describeBlueGreenParam.BlueGreenDeploymentIdentifier == nil && len(s.describeBlueGreenParam.Filters) == 0 {
+		return nil, errors.New("blue/green deployment identifier or name is required")
+	}
+	out, err := s.core.DescribeBlueGreenDeployments(ctx, s.describeBlueGreenParam)
+	if err = wrapError(err); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(out.BlueGreenDeployments) == 0 {
+		return nil, nil
+	}
+	return convertBlueGreenDeployment(&out.BlueGreenDeployments[0]), nil
+}
+
+func (s *rdsBlueGreen) Switchover(ctx context.Context) error {
+	param := *s.switchoverBlueGreenParam
+	id, err := s.identifier(ctx)
+	if err != nil {
+		return err
+	}
+	param.BlueGreenDeploymentIdentifier = id
+	_, err = s.core.SwitchoverBlueGreenDeployment(ctx, &param)
+	return wrapError(err)
+}
+
+func (s *rdsBlueGreen) Delete(ctx context.Context) error {
+	param := *s.deleteBlueGreenParam
+	id, err := s.identifier(ctx)
+	if err != nil {
+		return err
+	}
+	param.BlueGreenDeploymentIdentifier = id
+	_, err = s.core.DeleteBlueGreenDeployment(ctx, &param)
+	return wrapError(err)
+}
+
+// identifier returns the identifier of the deployment, looked up by name
+// when it is not set.
+func (s *rdsBlueGreen) identifier(ctx context.Context) (*string, error) {
+	if id := s.switchoverBlueGreenParam.BlueGreenDeploymentIdentifier; id != nil {
+		return id, nil
+	}
+	desc, err := s.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, fmt.Errorf("blue/green deployment %s: %w", aws.ToString(s.createBlueGreenParam.BlueGreenDeploymentName), ErrNotFound)
+	}
+	return aws.String(desc.BlueGreenDeploymentIdentifier), nil
+}
+
+// WaitFor waits until the deployment reaches status.
+func (s *rdsBlueGreen) WaitFor(ctx context.Context, status string, opts *WaitOptions) error {
+	id := aws.ToString(s.describeBlueGreenParam.BlueGreenDeploymentIdentifier)
+	if id == "" {
+		id = aws.ToString(s.createBlueGreenParam.BlueGreenDeploymentName)
+	}
+	return waitFor(ctx, id, status, opts, func(ctx context.Context) (string, error) {
+		desc, err := s.Describe(ctx)
+		if err != nil || desc == nil {
+			return "", err
+		}
+		return desc.Status, nil
+	}, isTerminalBlueGreenStatus)
+}
+
+func (s *rdsBlueGreen) WaitForSwitchover(ctx context.Context, opts *WaitOptions) error {
+	return s.WaitFor(ctx, BlueGreenStatusSwitchoverCompleted, opts)
+}
+
+func isTerminalBlueGreenStatus(status string) bool {
+	switch status {
+	case BlueGreenStatusSwitchoverFailed, BlueGreenStatusInvalidConfiguration, BlueGreenStatusProvisioningFailed, BlueGreenStatusDeleting:
+		return true
+	}
+	return false
+}
+
+// checkBlueGreenInstance checks that the instance replicates with binary
+// logs, enabled by automated backups, or with postgresql logical replication,
+// and that its parameters are applied.
+func checkBlueGreenInstance(desc *DescInstance, params []Parameter) []PreflightIssue {
+	var issues []PreflightIssue
+	if desc.DBInstanceStatus != DBInstanceStatusAvailable {
+		issues = append(issues, PreflightIssue{Check: "status", Message: fmt.Sprintf("instance is %s, not available", desc.DBInstanceStatus)})
+	}
+	if desc.ReadReplicaSourceDBInstanceIdentifier != "" {
+		issues = append(issues, PreflightIssue{Check: "replica", Message: "the source is a read replica"})
+	}
+	if strings.Contains(desc.Engine, "mysql") || desc.Engine == "mariadb" {
+		if desc.BackupRetentionPeriod == 0 {
+			issues = append(issues, PreflightIssue{Check: "binlog", Message: "automated backups are disabled, so binary logging is off"})
+		}
+		issues = append(issues, checkBinlogFormat(params)...)
+	}
+	if strings.Contains(desc.Engine, "postgres") {
+		issues = append(issues, checkLogicalReplication(params)...)
+	}
+	for _, group := range desc.DBParameterGroups {
+		if group.ApplyStatus != parameterApplyStatusInSync {
+			issues = append(issues, PreflightIssue{Check: "parameter group", Message: fmt.Sprintf("%s is %s, reboot to apply it", group.Name, group.ApplyStatus)})
+		}
+	}
+	return issues
+}
+
+// checkBlueGreenCluster checks that the cluster writes binary logs, or enables
+// postgresql logical replication, and that the cluster parameter group is
+// applied to every member.
+func checkBlueGreenCluster(desc *DescCluster, params []Parameter) []PreflightIssue {
+	var issues []PreflightIssue
+	if desc.Status != string(DBClusterStatusAvailable) {
+		issues = append(issues, PreflightIssue{Check: "status", Message: fmt.Sprintf("cluster is %s, not available", desc.Status)})
+	}
+	if desc.ReplicationSourceIdentifier != "" {
+		issues = append(issues, PreflightIssue{Check: "replica", Message: "the source is a replica cluster"})
+	}
+	if strings.Contains(desc.Engine, "mysql") {
+		issues = append(issues, checkBinlogFormat(params)...)
+	}
+	if strings.Contains(desc.Engine, "postgres") {
+		issues = append(issues, checkLogicalReplication(params)...)
+	}
+	for _, m := range desc.DBClusterMembers {
+		if m.DBClusterParameterGroupStatus != parameterApplyStatusInSync {
+			issues = append(issues, PreflightIssue{Check: "parameter group", Message: fmt.Sprintf("%s of %s is %s, reboot to apply it", desc.DBClusterParameterGroup, m.DBInstanceIdentifier, m.DBClusterParameterGroupStatus)})
+		}
+	}
+	return issues
+}
+
+func checkBinlogFormat(params []Parameter) []PreflightIssue {
+	for _, p := range params {
+		if p.Name != "binlog_format" {
+			continue
+		}
+		if p.Value == "" || strings.EqualFold(p.Value, "OFF") {
+			break
+		}
+		return nil
+	}
+	return []PreflightIssue{{Check: "binlog", Message: "binlog_format must be set, ROW is recommended"}}
+}
+
+func checkLogicalReplication(params []Parameter) []PreflightIssue {
+	for _, p := range params {
+		if p.Name == logicalReplicationParameter && p.Value == "1" {
+			return nil
+		}
+	}
+	return []PreflightIssue{{Check: "logical replication", Message: logicalReplicationParameter + " must be 1"}}
+}
+
+func convertBlueGreenDeployment(in *types.BlueGreenDeployment) *DescBlueGreenDeployment {
+	if in == nil {
+		return nil
+	}
+	desc := &DescBlueGreenDeployment{
+		BlueGreenDeploymentIdentifier: aws.ToString(in.BlueGreenDeploymentIdentifier),
+		BlueGreenDeploymentName:       aws.ToString(in.BlueGreenDeploymentName),
+		Source:                        aws.ToString(in.Source),
+		Target:                        aws.ToString(in.Target),
+		Status:                        aws.ToString(in.Status),
+		StatusDetails:                 aws.ToString(in.StatusDetails),
+		CreateTime:                    aws.ToTime(in.CreateTime),
+		DeleteTime:                    aws.ToTime(in.DeleteTime),
+		Tags:                          convertTags(in.TagList),
+	}
+	for _, d := range in.SwitchoverDetails {
+		desc.SwitchoverDetails = append(desc.SwitchoverDetails, SwitchoverDetail{
+			SourceMember: aws.ToString(d.SourceMember),
+			TargetMember: aws.ToString(d.TargetMember),
+			Status:       aws.ToString(d.Status),
+		})
+	}
+	for _, t := range in.Tasks {
+		desc.Tasks = append(desc.Tasks, BlueGreenTask{Name: aws.ToString(t.Name), Status: aws.ToString(t.Status)})
+	}
+	return desc
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BlueGreen", func() {
+	It("should feed the deployment inputs from the setters", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).BlueGreen().
+			SetBlueGreenDeploymentName("shop-upgrade").
+			SetSource("arn:aws:rds:us-east-1:123456789012:db:shop").
+			SetTargetEngineVersion("8.0.32").
+			SetSwitchoverTimeout(5 * time.Minute).(*rdsBlueGreen)
+
+		Expect(aws.ToString(b.createBlueGreenParam.BlueGreenDeploymentName)).To(Equal("shop-upgrade"))
+		Expect(aws.ToString(b.describeBlueGreenParam.Filters[0].Name)).To(Equal("blue-green-deployment-name"))
+		Expect(aws.ToInt32(b.switchoverBlueGreenParam.SwitchoverTimeout)).To(Equal(int32(300)))
+	})
+
+	It("should pass an instance ready for a blue/green deployment", func() {
+		desc := &DescInstance{
+			DBInstanceStatus:      DBInstanceStatusAvailable,
+			Engine:                "mysql",
+			BackupRetentionPeriod: 7,
+			DBParameterGroups:     []ParameterGroupStatus{{Name: "shop", ApplyStatus: "in-sync"}},
+		}
+		Expect(checkBlueGreenInstance(desc, []Parameter{{Name: "binlog_format", Value: "ROW"}})).To(BeEmpty())
+	})
+
+	It("should report the missing prerequisites of an instance", func() {
+		desc := &DescInstance{
+			DBInstanceStatus:  "modifying",
+			Engine:            "mysql",
+			DBParameterGroups: []ParameterGroupStatus{{Name: "shop", ApplyStatus: "pending-reboot"}},
+		}
+		var checks []string
+		for _, issue := range checkBlueGreenInstance(desc, nil) {
+			checks = append(checks, issue.Check)
+		}
+		Expect(checks).To(Equal([]string{"status", "binlog", "binlog", "parameter group"}))
+	})
+
+	It("should report an aurora cluster without binary logs", func() {
+		desc := &DescCluster{
+			Status:                  "available",
+			Engine:                  "aurora-mysql",
+			DBClusterParameterGroup: "default.aurora-mysql8.0",
+			DBClusterMembers:        []ClusterMember{{DBInstanceIdentifier: "shop-1", DBClusterParameterGroupStatus: "in-sync"}},
+		}
+		issues := checkBlueGreenCluster(desc, []Parameter{{Name: "binlog_format", Value: "OFF"}})
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Check).To(Equal("binlog"))
+
+		err := error(&PreflightError{Source: "shop", Issues: issues})
+		Expect(errors.Is(err, ErrPreflight)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("binlog_format must be set"))
+	})
+
+	It("should require logical replication of a postgresql source", func() {
+		ins := &DescInstance{
+			DBInstanceStatus:      DBInstanceStatusAvailable,
+			Engine:                "postgres",
+			BackupRetentionPeriod: 7,
+			DBParameterGroups:     []ParameterGroupStatus{{Name: "shop", ApplyStatus: "in-sync"}},
+		}
+		issues := checkBlueGreenInstance(ins, []Parameter{{Name: "rds.logical_replication", Value: "0"}})
+		Expect(issues).To(Equal([]PreflightIssue{{Check: "logical replication", Message: "rds.logical_replication must be 1"}}))
+		Expect(checkBlueGreenInstance(ins, []Parameter{{Name: "rds.logical_replication", Value: "1"}})).To(BeEmpty())
+
+		cluster := &DescCluster{Status: "available", Engine: "aurora-postgresql"}
+		Expect(checkBlueGreenCluster(cluster, nil)).To(HaveLen(1))
+		Expect(checkBlueGreenCluster(cluster, []Parameter{{Name: "rds.logical_replication", Value: "1"}})).To(BeEmpty())
+	})
+})
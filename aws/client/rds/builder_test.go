@@ -0,0 +1,92 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Builders", func() {
+	It("should return a fresh instance builder on every call", func() {
+		svc := NewService(aws.Config{Region: "us-east-1"})
+
+		var wg sync.WaitGroup
+		builders := make([]*rdsInstance, 8)
+		for i := range builders {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				builders[i] = svc.Instance().SetDBInstanceIdentifier(string(rune('a' + i))).(*rdsInstance)
+			}(i)
+		}
+		wg.Wait()
+
+		for i, b := range builders {
+			Expect(aws.ToString(b.createInstanceParam.DBInstanceIdentifier)).To(Equal(string(rune('a' + i))))
+			Expect(b.core).To(BeIdenticalTo(svc.core))
+		}
+		Expect(svc.Instance().(*rdsInstance).createInstanceParam.DBInstanceIdentifier).To(BeNil())
+		Expect(svc.Cluster()).ToNot(BeIdenticalTo(svc.Cluster()))
+		Expect(svc.Aurora()).ToNot(BeIdenticalTo(svc.Aurora()))
+	})
+
+	It("should replace the values of an existing filter", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).Instance().
+			SetFilter("db-cluster-id", []string{"foo"}).
+			SetFilter("engine", []string{"mysql"}).
+			SetFilter("db-cluster-id", []string{"bar"}).(*rdsInstance)
+
+		filters := b.describeInstanceParam.Filters
+		Expect(filters).To(HaveLen(2))
+		Expect(aws.ToString(filters[0].Name)).To(Equal("db-cluster-id"))
+		Expect(filters[0].Values).To(Equal([]string{"bar"}))
+	})
+
+	It("should feed the modify input from the setters", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).Instance().
+			SetDBInstanceIdentifier("foo").
+			SetDBInstanceClass("db.r6g.large").
+			SetMaxAllocatedStorage(200).
+			SetDeletionProtection(true).
+			SetApplyImmediately(true).(*rdsInstance)
+
+		Expect(aws.ToString(b.modifyInstanceParam.DBInstanceIdentifier)).To(Equal("foo"))
+		Expect(aws.ToString(b.modifyInstanceParam.DBInstanceClass)).To(Equal("db.r6g.large"))
+		Expect(aws.ToInt32(b.modifyInstanceParam.MaxAllocatedStorage)).To(Equal(int32(200)))
+		Expect(aws.ToBool(b.modifyInstanceParam.DeletionProtection)).To(BeTrue())
+		Expect(aws.ToBool(b.createInstanceParam.DeletionProtection)).To(BeTrue())
+		Expect(b.modifyInstanceParam.ApplyImmediately).To(BeTrue())
+	})
+
+	It("should convert the pending modified values", func() {
+		desc := convertDBInstance(&types.DBInstance{
+			DBInstanceClass: aws.String("db.t3.micro"),
+			PendingModifiedValues: &types.PendingModifiedValues{
+				DBInstanceClass:    aws.String("db.r6g.large"),
+				MasterUserPassword: aws.String("****"),
+			},
+		})
+		Expect(desc.DBInstanceClass).To(Equal("db.t3.micro"))
+		Expect(desc.PendingModifiedValues.DBInstanceClass).To(Equal("db.r6g.large"))
+		Expect(desc.PendingModifiedValues.MasterUserPasswordPending).To(BeTrue())
+
+		Expect(convertDBCluster(&types.DBCluster{}).PendingModifiedValues).To(BeNil())
+	})
+})
@@ -0,0 +1,61 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// fakeClient returns a client answering every call with the error code, or
+// with the body when code is empty, without reaching the network.
+func fakeClient(code, body string) *rds.Client {
+	return serveClient(func(*http.Request) (int, string) {
+		if code != "" {
+			return http.StatusNotFound, fmt.Sprintf(`<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>%s</Message></Error><RequestId>fake</RequestId></ErrorResponse>`, code, code)
+		}
+		return http.StatusOK, body
+	})
+}
+
+// serveClient returns a client whose calls are answered by serve, with the
+// status and the xml body it returns.
+func serveClient(serve func(req *http.Request) (int, string)) *rds.Client {
+	return rds.New(rds.Options{
+		Region:      "us-east-1",
+		Credentials: aws.AnonymousCredentials{},
+		Retryer:     aws.NopRetryer{},
+		HTTPClient: doerFunc(func(req *http.Request) (*http.Response, error) {
+			status, body := serve(req)
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Content-Type": []string{"text/xml"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	})
+}
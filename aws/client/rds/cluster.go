@@ -17,7 +17,7 @@ package rds
 import (
 	"context"
 	"errors"
-	"github.com/aws/smithy-go"
+	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -46,6 +46,12 @@ const (
 	DBClusterStatusStopping        DBClusterStatus = "stopping"
 	DBClusterStatusStopped         DBClusterStatus = "stopped"
 	DBClusterStatusUpgrading       DBClusterStatus = "upgrading"
+
+	DBClusterStatusInaccessibleEncryptionCredentials DBClusterStatus = "inaccessible-encryption-credentials"
+
+	// DBClusterStatusDeleted is not reported by AWS, WaitFor uses it to wait
+	// until the cluster is not found anymore.
+	DBClusterStatusDeleted DBClusterStatus = "deleted"
 )
 
 type DBClusterRestoreType string
@@ -83,18 +89,49 @@ type Cluster interface {
 	SetSnapshotIdentifier(id string) Cluster
 	SetFinalDBSnapshotIdentifier(id string) Cluster
 	SetSkipSnapshot(bool) Cluster
+	SetWatchInterval(interval time.Duration) Cluster
+	SetFilter(name string, values []string) Cluster
+	SetSnapshotFilter(name string, values []string) Cluster
+	SetMaxResults(max int32) Cluster
+	SetTargetSnapshotIdentifier(id string) Cluster
+	SetCopyTags(enable bool) Cluster
+	SetKmsKeyId(id string) Cluster
+	SetSourceRegion(region string) Cluster
+	SetBackupRetentionPeriod(days int32) Cluster
+	SetPreferredMaintenanceWindow(window string) Cluster
+	SetDeletionProtection(enable bool) Cluster
+	SetDBClusterParameterGroupName(name string) Cluster
+	SetOptionGroupName(name string) Cluster
+	SetServerlessV2Scaling(min, max float64) Cluster
+	SetTags(tags map[string]string) Cluster
+	SetApplyImmediately(enable bool) Cluster
 
 	Failover(context.Context) error
 	FailoverGlobal(context.Context) error
 	Create(context.Context) error
 	Delete(context.Context) error
 	Reboot(context.Context) error
+	Modify(context.Context) error
+	Start(context.Context) error
+	Stop(context.Context) error
 	Describe(context.Context) (*DescCluster, error)
+	List(context.Context) ([]*DescCluster, error)
+	Iterate(ctx context.Context, fn func(*DescCluster) bool) error
+	ListSnapshots(context.Context) ([]*DescClusterSnapshot, error)
+	IterateSnapshots(ctx context.Context, fn func(*DescClusterSnapshot) bool) error
 	RestorePitr(context.Context) error
 	CreateSnapshot(context.Context) error
 	DescribeSnapshot(context.Context) (*DescClusterSnapshot, error)
+	DeleteSnapshot(context.Context) error
+	CopySnapshot(context.Context) error
+	WaitForSnapshot(ctx context.Context, opts *WaitOptions) error
+	ShareSnapshot(ctx context.Context, accounts ...string) error
+	UnshareSnapshot(ctx context.Context, accounts ...string) error
 	RestoreFromSnapshot(context.Context) error
 	RestoreToPitr(context.Context) error
+	WaitFor(ctx context.Context, status DBClusterStatus, opts *WaitOptions) error
+	Watch(ctx context.Context) <-chan StatusEvent
+	Events(ctx context.Context, sourceID string, since time.Time) ([]*Event, error)
 }
 
 type rdsCluster struct {
@@ -109,6 +146,36 @@ type rdsCluster struct {
 	restoreDBClusterFromSnapshotParam *rds.RestoreDBClusterFromSnapshotInput
 	createDBClusterSnapshotParam      *rds.CreateDBClusterSnapshotInput
 	describeDBClusterSnapshotParam    *rds.DescribeDBClusterSnapshotsInput
+	modifyClusterParam                *rds.ModifyDBClusterInput
+	startClusterParam                 *rds.StartDBClusterInput
+	stopClusterParam                  *rds.StopDBClusterInput
+	deleteClusterSnapshotParam        *rds.DeleteDBClusterSnapshotInput
+	copyClusterSnapshotParam          *rds.CopyDBClusterSnapshotInput
+
+	maxResults    int32
+	tagFilters    []types.Filter
+	watchInterval time.Duration
+}
+
+func newCluster(core *rds.Client) *rdsCluster {
+	return &rdsCluster{
+		core:                              core,
+		createClusterParam:                &rds.CreateDBClusterInput{},
+		deleteClusterParam:                &rds.DeleteDBClusterInput{},
+		failoverClusterParam:              &rds.FailoverDBClusterInput{},
+		failoverGlobalClusterParam:        &rds.FailoverGlobalClusterInput{},
+		rebootClusterParam:                &rds.RebootDBClusterInput{},
+		describeClusterParam:              &rds.DescribeDBClustersInput{},
+		restoreDBClusterPitrParam:         &rds.RestoreDBClusterToPointInTimeInput{},
+		restoreDBClusterFromSnapshotParam: &rds.RestoreDBClusterFromSnapshotInput{},
+		createDBClusterSnapshotParam:      &rds.CreateDBClusterSnapshotInput{},
+		describeDBClusterSnapshotParam:    &rds.DescribeDBClusterSnapshotsInput{},
+		modifyClusterParam:                &rds.ModifyDBClusterInput{},
+		startClusterParam:                 &rds.StartDBClusterInput{},
+		stopClusterParam:                  &rds.StopDBClusterInput{},
+		deleteClusterSnapshotParam:        &rds.DeleteDBClusterSnapshotInput{},
+		copyClusterSnapshotParam:          &rds.CopyDBClusterSnapshotInput{},
+	}
 }
 
 func (s *rdsCluster) SetSkipSnapshot(enable bool) Cluster {
@@ -126,6 +193,9 @@ func (s *rdsCluster) SetDBClusterIdentifier(id string) Cluster {
 	s.createDBClusterSnapshotParam.DBClusterIdentifier = aws.String(id)
 	s.restoreDBClusterPitrParam.DBClusterIdentifier = aws.String(id)
 	s.restoreDBClusterFromSnapshotParam.DBClusterIdentifier = aws.String(id)
+	s.modifyClusterParam.DBClusterIdentifier = aws.String(id)
+	s.startClusterParam.DBClusterIdentifier = aws.String(id)
+	s.stopClusterParam.DBClusterIdentifier = aws.String(id)
 	return s
 }
 
@@ -136,11 +206,13 @@ func (s *rdsCluster) SetTargetDBInstanceIdentifier(id string) Cluster {
 
 func (s *rdsCluster) Failover(ctx context.Context) error {
 	_, err := s.core.FailoverDBCluster(ctx, s.failoverClusterParam)
-	return err
+	return wrapError(err)
 }
 
-// FailoverGlobalClusterInput
+// SetGlobalClusterIdentifier sets the global cluster of FailoverGlobal, and
+// makes the cluster created by Create a secondary cluster of it.
 func (s *rdsCluster) SetGlobalClusterIdentifier(id string) Cluster {
+	s.createClusterParam.GlobalClusterIdentifier = aws.String(id)
 	s.failoverGlobalClusterParam.GlobalClusterIdentifier = aws.String(id)
 	return s
 }
@@ -152,7 +224,7 @@ func (s *rdsCluster) SetTargetDbClusterIdentifier(id string) Cluster {
 
 func (s *rdsCluster) FailoverGlobal(ctx context.Context) error {
 	_, err := s.core.FailoverGlobalCluster(ctx, s.failoverGlobalClusterParam)
-	return err
+	return wrapError(err)
 }
 
 // CreateDBClusterInput
@@ -164,6 +236,7 @@ func (s *rdsCluster) SetEngine(engine string) Cluster {
 
 func (s *rdsCluster) SetAllocatedStorage(size int32) Cluster {
 	s.createClusterParam.AllocatedStorage = aws.Int32(size)
+	s.modifyClusterParam.AllocatedStorage = aws.Int32(size)
 	return s
 }
 
@@ -177,6 +250,7 @@ func (s *rdsCluster) SetDBClusterInstanceClass(class string) Cluster {
 	s.createClusterParam.DBClusterInstanceClass = aws.String(class)
 	s.restoreDBClusterPitrParam.DBClusterInstanceClass = aws.String(class)
 	s.restoreDBClusterFromSnapshotParam.DBClusterInstanceClass = aws.String(class)
+	s.modifyClusterParam.DBClusterInstanceClass = aws.String(class)
 	return s
 }
 
@@ -212,6 +286,7 @@ func (s *rdsCluster) SetMasterUsername(username string) Cluster {
 
 func (s *rdsCluster) SetMasterUserPassword(pass string) Cluster {
 	s.createClusterParam.MasterUserPassword = aws.String(pass)
+	s.modifyClusterParam.MasterUserPassword = aws.String(pass)
 	return s
 }
 
@@ -226,6 +301,7 @@ func (s *rdsCluster) SetStorageType(t string) Cluster {
 	s.createClusterParam.StorageType = aws.String(t)
 	s.restoreDBClusterFromSnapshotParam.StorageType = aws.String(t)
 	s.restoreDBClusterPitrParam.StorageType = aws.String(t)
+	s.modifyClusterParam.StorageType = aws.String(t)
 	return s
 }
 
@@ -233,12 +309,75 @@ func (s *rdsCluster) SetIOPS(ps int32) Cluster {
 	s.createClusterParam.Iops = aws.Int32(ps)
 	s.restoreDBClusterPitrParam.Iops = aws.Int32(ps)
 	s.restoreDBClusterFromSnapshotParam.Iops = aws.Int32(ps)
+	s.modifyClusterParam.Iops = aws.Int32(ps)
+	return s
+}
+
+func (s *rdsCluster) SetBackupRetentionPeriod(days int32) Cluster {
+	s.createClusterParam.BackupRetentionPeriod = aws.Int32(days)
+	s.modifyClusterParam.BackupRetentionPeriod = aws.Int32(days)
+	return s
+}
+
+// SetPreferredMaintenanceWindow sets the weekly maintenance window in UTC, formatted ddd:hh24:mi-ddd:hh24:mi.
+func (s *rdsCluster) SetPreferredMaintenanceWindow(window string) Cluster {
+	s.createClusterParam.PreferredMaintenanceWindow = aws.String(window)
+	s.modifyClusterParam.PreferredMaintenanceWindow = aws.String(window)
 	return s
 }
 
+func (s *rdsCluster) SetDeletionProtection(enable bool) Cluster {
+	s.createClusterParam.DeletionProtection = aws.Bool(enable)
+	s.restoreDBClusterPitrParam.DeletionProtection = aws.Bool(enable)
+	s.restoreDBClusterFromSnapshotParam.DeletionProtection = aws.Bool(enable)
+	s.modifyClusterParam.DeletionProtection = aws.Bool(enable)
+	return s
+}
+
+func (s *rdsCluster) SetDBClusterParameterGroupName(name string) Cluster {
+	s.createClusterParam.DBClusterParameterGroupName = aws.String(name)
+	s.restoreDBClusterPitrParam.DBClusterParameterGroupName = aws.String(name)
+	s.restoreDBClusterFromSnapshotParam.DBClusterParameterGroupName = aws.String(name)
+	s.modifyClusterParam.DBClusterParameterGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsCluster) SetOptionGroupName(name string) Cluster {
+	s.createClusterParam.OptionGroupName = aws.String(name)
+	s.restoreDBClusterPitrParam.OptionGroupName = aws.String(name)
+	s.restoreDBClusterFromSnapshotParam.OptionGroupName = aws.String(name)
+	s.modifyClusterParam.OptionGroupName = aws.String(name)
+	return s
+}
+
+// SetApplyImmediately applies Modify now instead of during the next maintenance window.
+func (s *rdsCluster) SetApplyImmediately(enable bool) Cluster {
+	s.modifyClusterParam.ApplyImmediately = enable
+	return s
+}
+
+// Modify changes the cluster with the values set by SetDBClusterInstanceClass,
+// SetAllocatedStorage, SetIOPS, SetStorageType, SetBackupRetentionPeriod,
+// SetPreferredMaintenanceWindow, SetDeletionProtection,
+// SetDBClusterParameterGroupName and SetMasterUserPassword.
+func (s *rdsCluster) Modify(ctx context.Context) error {
+	if err := validateServerlessV2Modify(s.modifyClusterParam.ServerlessV2ScalingConfiguration); err != nil {
+		return err
+	}
+	_, err := s.core.ModifyDBCluster(ctx, s.modifyClusterParam)
+	return wrapError(err)
+}
+
 func (s *rdsCluster) Create(ctx context.Context) error {
+	if aws.ToString(s.createClusterParam.DBClusterInstanceClass) == DBInstanceClassServerless {
+		return fmt.Errorf("multi-AZ DB clusters do not support %s: %w", DBInstanceClassServerless, ErrServerlessUnsupported)
+	}
+	p := s.createClusterParam
+	if err := validateServerlessV2Create(p.Engine, p.EngineVersion, p.EngineMode, p.ServerlessV2ScalingConfiguration, nil); err != nil {
+		return err
+	}
 	_, err := s.core.CreateDBCluster(ctx, s.createClusterParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsCluster) SetSkipFinalSnapshot(skip bool) Cluster {
@@ -255,18 +394,27 @@ func (s *rdsCluster) SetPublicAccessible(enable bool) Cluster {
 
 func (s *rdsCluster) Delete(ctx context.Context) error {
 	_, err := s.core.DeleteDBCluster(ctx, s.deleteClusterParam)
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBClusterNotFoundFault); !ok {
-			return err
-		}
+	if err = wrapError(err); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
 	}
 	return nil
 }
 
+func (s *rdsCluster) Start(ctx context.Context) error {
+	_, err := s.core.StartDBCluster(ctx, s.startClusterParam)
+	return wrapError(err)
+}
+
+// Stop stops the cluster and its instances, which rds starts again by itself after seven days.
+func (s *rdsCluster) Stop(ctx context.Context) error {
+	_, err := s.core.StopDBCluster(ctx, s.stopClusterParam)
+	return wrapError(err)
+}
+
 // RebootDBClusterInput
 func (s *rdsCluster) Reboot(ctx context.Context) error {
 	_, err := s.core.RebootDBCluster(ctx, s.rebootClusterParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsCluster) SetSourceDBClusterIdentifier(sid string) Cluster {
@@ -297,12 +445,14 @@ func (s *rdsCluster) SetUseLatestRestorableTime(enable bool) Cluster {
 
 func (s *rdsCluster) RestorePitr(ctx context.Context) error {
 	_, err := s.core.RestoreDBClusterToPointInTime(ctx, s.restoreDBClusterPitrParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsCluster) SetSnapshotIdentifier(id string) Cluster {
 	s.createDBClusterSnapshotParam.DBClusterSnapshotIdentifier = aws.String(id)
 	s.describeDBClusterSnapshotParam.DBClusterSnapshotIdentifier = aws.String(id)
+	s.deleteClusterSnapshotParam.DBClusterSnapshotIdentifier = aws.String(id)
+	s.copyClusterSnapshotParam.SourceDBClusterSnapshotIdentifier = aws.String(id)
 	s.restoreDBClusterFromSnapshotParam.SnapshotIdentifier = aws.String(id)
 	return s
 }
@@ -314,11 +464,8 @@ func (s *rdsCluster) SetFinalDBSnapshotIdentifier(id string) Cluster {
 
 func (s *rdsCluster) CreateSnapshot(ctx context.Context) error {
 	snapshot, err := s.DescribeSnapshot(ctx)
-
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBClusterSnapshotNotFoundFault); !ok {
-			return err
-		}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
 	}
 
 	if snapshot != nil {
@@ -326,13 +473,13 @@ func (s *rdsCluster) CreateSnapshot(ctx context.Context) error {
 	}
 
 	_, err = s.core.CreateDBClusterSnapshot(ctx, s.createDBClusterSnapshotParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsCluster) DescribeSnapshot(ctx context.Context) (*DescClusterSnapshot, error) {
 	snapshots, err := s.core.DescribeDBClusterSnapshots(ctx, s.describeDBClusterSnapshotParam)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 	if len(snapshots.DBClusterSnapshots) == 0 {
 		return nil, nil
@@ -359,6 +506,36 @@ type DescCluster struct {
 	Port                        int32
 	EarliestRestorableTime      time.Time
 	LatestRestorableTime        time.Time
+	Engine                      string
+	EngineVersion               string
+	DBClusterInstanceClass      string
+	AllocatedStorage            int32
+	Iops                        int32
+	StorageType                 string
+	BackupRetentionPeriod       int32
+	PreferredMaintenanceWindow  string
+	PendingModifiedValues       *ClusterPendingModifiedValues
+	Tags                        map[string]string
+	EngineMode                  string
+	ServerlessV2Scaling         *ServerlessV2Scaling
+	// Capacity is the current capacity of a Serverless v1 cluster. The
+	// current capacity of Serverless v2 instances is a CloudWatch metric.
+	Capacity int32
+	// CustomEndpointDetails describes the CustomEndpoints of an Aurora
+	// cluster with the members they route to. It is set by rds.Aurora only.
+	CustomEndpointDetails []CustomEndpoint
+}
+
+// ClusterPendingModifiedValues are the changes of a modification which are
+// not applied yet. Zero values are not pending.
+type ClusterPendingModifiedValues struct {
+	DBClusterIdentifier       string
+	AllocatedStorage          int32
+	Iops                      int32
+	BackupRetentionPeriod     int32
+	EngineVersion             string
+	MasterUserPasswordPending bool
+	IAMDatabaseAuthentication *bool
 }
 
 type ClusterMember struct {
@@ -378,6 +555,7 @@ type DescClusterSnapshot struct {
 	SnapshotCreateTime          time.Time
 	SnapshotType                string
 	Status                      string
+	Tags                        map[string]string
 }
 
 // convertDBCluster converts aws types.DBCluster to DescCluster
@@ -400,6 +578,34 @@ func convertDBCluster(in *types.DBCluster) *DescCluster {
 		Port:                        aws.ToInt32(in.Port),
 		EarliestRestorableTime:      aws.ToTime(in.EarliestRestorableTime),
 		LatestRestorableTime:        aws.ToTime(in.LatestRestorableTime),
+		Engine:                      aws.ToString(in.Engine),
+		EngineVersion:               aws.ToString(in.EngineVersion),
+		DBClusterInstanceClass:      aws.ToString(in.DBClusterInstanceClass),
+		AllocatedStorage:            aws.ToInt32(in.AllocatedStorage),
+		Iops:                        aws.ToInt32(in.Iops),
+		StorageType:                 aws.ToString(in.StorageType),
+		BackupRetentionPeriod:       aws.ToInt32(in.BackupRetentionPeriod),
+		PreferredMaintenanceWindow:  aws.ToString(in.PreferredMaintenanceWindow),
+		PendingModifiedValues:       convertClusterPendingModifiedValues(in.PendingModifiedValues),
+		Tags:                        convertTags(in.TagList),
+		EngineMode:                  aws.ToString(in.EngineMode),
+		Capacity:                    aws.ToInt32(in.Capacity),
+		ServerlessV2Scaling:         convertServerlessV2Scaling(in.ServerlessV2ScalingConfiguration),
+	}
+}
+
+func convertClusterPendingModifiedValues(in *types.ClusterPendingModifiedValues) *ClusterPendingModifiedValues {
+	if in == nil {
+		return nil
+	}
+	return &ClusterPendingModifiedValues{
+		DBClusterIdentifier:       aws.ToString(in.DBClusterIdentifier),
+		AllocatedStorage:          aws.ToInt32(in.AllocatedStorage),
+		Iops:                      aws.ToInt32(in.Iops),
+		BackupRetentionPeriod:     aws.ToInt32(in.BackupRetentionPeriod),
+		EngineVersion:             aws.ToString(in.EngineVersion),
+		MasterUserPasswordPending: in.MasterUserPassword != nil,
+		IAMDatabaseAuthentication: in.IAMDatabaseAuthenticationEnabled,
 	}
 }
 
@@ -417,9 +623,8 @@ func convertDBClusterMembers(in []types.DBClusterMember) []ClusterMember {
 
 func (s *rdsCluster) Describe(ctx context.Context) (*DescCluster, error) {
 	output, err := s.core.DescribeDBClusters(ctx, s.describeClusterParam)
-
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBClusterNotFoundFault); ok {
+	if err = wrapError(err); err != nil {
+		if errors.Is(err, ErrNotFound) {
 			return nil, nil
 		}
 		return nil, err
@@ -444,13 +649,14 @@ func convertDBClusterSnapshot(in *types.DBClusterSnapshot) *DescClusterSnapshot
 		SnapshotCreateTime:          aws.ToTime(in.SnapshotCreateTime),
 		SnapshotType:                aws.ToString(in.SnapshotType),
 		Status:                      aws.ToString(in.Status),
+		Tags:                        convertTags(in.TagList),
 	}
 }
 
 func (s *rdsCluster) RestoreFromSnapshot(ctx context.Context) error {
 	_, err := s.core.RestoreDBClusterFromSnapshot(ctx, s.restoreDBClusterFromSnapshotParam)
 	if err != nil {
-		return err
+		return wrapError(err)
 	}
 
 	return nil
@@ -459,7 +665,7 @@ func (s *rdsCluster) RestoreFromSnapshot(ctx context.Context) error {
 func (s *rdsCluster) RestoreToPitr(ctx context.Context) error {
 	_, err := s.core.RestoreDBClusterToPointInTime(ctx, s.restoreDBClusterPitrParam)
 	if err != nil {
-		return err
+		return wrapError(err)
 	}
 
 	return nil
@@ -0,0 +1,166 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+type CustomEndpointType string
+
+const (
+	// CustomEndpointTypeReader only routes to the reader instances.
+	CustomEndpointTypeReader CustomEndpointType = "READER"
+	// CustomEndpointTypeAny routes to the readers and the writer.
+	CustomEndpointTypeAny CustomEndpointType = "ANY"
+)
+
+// CustomEndpoint is an Aurora custom endpoint, as found in
+// DescCluster.CustomEndpointDetails.
+type CustomEndpoint struct {
+	Identifier      string
+	Arn             string
+	Endpoint        string
+	Type            CustomEndpointType
+	Status          string
+	StaticMembers   []string
+	ExcludedMembers []string
+	// Members are the instances the endpoint currently routes to.
+	Members []string
+}
+
+func (s *rdsAurora) SetEndpointIdentifier(id string) Aurora {
+	s.createEndpointParam.DBClusterEndpointIdentifier = aws.String(id)
+	s.modifyEndpointParam.DBClusterEndpointIdentifier = aws.String(id)
+	s.deleteEndpointParam.DBClusterEndpointIdentifier = aws.String(id)
+	return s
+}
+
+// SetEndpointType sets the type of the custom endpoint, CustomEndpointTypeReader by default.
+func (s *rdsAurora) SetEndpointType(t CustomEndpointType) Aurora {
+	s.createEndpointParam.EndpointType = aws.String(string(t))
+	s.modifyEndpointParam.EndpointType = aws.String(string(t))
+	return s
+}
+
+// SetStaticMembers restricts the custom endpoint to the instances ids.
+func (s *rdsAurora) SetStaticMembers(ids []string) Aurora {
+	s.createEndpointParam.StaticMembers = ids
+	s.modifyEndpointParam.StaticMembers = ids
+	return s
+}
+
+// SetExcludedMembers routes the custom endpoint to every instance but ids,
+// including the instances added to the cluster later.
+func (s *rdsAurora) SetExcludedMembers(ids []string) Aurora {
+	s.createEndpointParam.ExcludedMembers = ids
+	s.modifyEndpointParam.ExcludedMembers = ids
+	return s
+}
+
+// NewReadonlyEndpoint creates a custom endpoint of the cluster.
+func (s *rdsAurora) NewReadonlyEndpoint(ctx context.Context) error {
+	_, err := s.core.CreateDBClusterEndpoint(ctx, s.createEndpointParam)
+	return wrapError(err)
+}
+
+func (s *rdsAurora) ModifyReadonlyEndpoint(ctx context.Context) error {
+	_, err := s.core.ModifyDBClusterEndpoint(ctx, s.modifyEndpointParam)
+	return wrapError(err)
+}
+
+// DescribeReadonlyEndpoints returns the CustomEndpointDetails of the cluster.
+func (s *rdsAurora) DescribeReadonlyEndpoints(ctx context.Context) ([]CustomEndpoint, error) {
+	desc, err := s.Describe(ctx)
+	if err != nil || desc == nil {
+		return nil, err
+	}
+	return desc.CustomEndpointDetails, nil
+}
+
+func (s *rdsAurora) DeleteReadonlyEndpoint(ctx context.Context) error {
+	_, err := s.core.DeleteDBClusterEndpoint(ctx, s.deleteEndpointParam)
+	return wrapError(err)
+}
+
+// describeEndpoints sets the CustomEndpointDetails of desc, resolving the
+// members of the endpoints among the cluster members.
+func describeEndpoints(ctx context.Context, core *rds.Client, desc *DescCluster) error {
+	if len(desc.CustomEndpoints) == 0 {
+		return nil
+	}
+	param := &rds.DescribeDBClusterEndpointsInput{
+		DBClusterIdentifier: aws.String(desc.DBClusterIdentifier),
+		Filters:             setFilter(nil, "db-cluster-endpoint-type", []string{"custom"}),
+	}
+
+	var endpoints []CustomEndpoint
+	paginator := rds.NewDescribeDBClusterEndpointsPaginator(core, param)
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			return wrapError(err)
+		}
+		for i := range out.DBClusterEndpoints {
+			ep := convertCustomEndpoint(&out.DBClusterEndpoints[i])
+			ep.Members = customEndpointMembers(ep, desc.DBClusterMembers)
+			endpoints = append(endpoints, ep)
+		}
+	}
+	desc.CustomEndpointDetails = endpoints
+	return nil
+}
+
+func convertCustomEndpoint(in *types.DBClusterEndpoint) CustomEndpoint {
+	return CustomEndpoint{
+		Identifier:      aws.ToString(in.DBClusterEndpointIdentifier),
+		Arn:             aws.ToString(in.DBClusterEndpointArn),
+		Endpoint:        aws.ToString(in.Endpoint),
+		Type:            CustomEndpointType(aws.ToString(in.CustomEndpointType)),
+		Status:          aws.ToString(in.Status),
+		StaticMembers:   in.StaticMembers,
+		ExcludedMembers: in.ExcludedMembers,
+	}
+}
+
+// customEndpointMembers returns the static members of the endpoint, or else
+// the cluster members which are not excluded. The writer is left out of
+// CustomEndpointTypeReader endpoints.
+func customEndpointMembers(ep CustomEndpoint, members []ClusterMember) []string {
+	static := map[string]bool{}
+	for _, id := range ep.StaticMembers {
+		static[id] = true
+	}
+	excluded := map[string]bool{}
+	for _, id := range ep.ExcludedMembers {
+		excluded[id] = true
+	}
+
+	var out []string
+	for _, m := range members {
+		switch {
+		case m.IsClusterWrite && ep.Type == CustomEndpointTypeReader:
+		case len(static) > 0 && !static[m.DBInstanceIdentifier]:
+		case excluded[m.DBInstanceIdentifier]:
+		default:
+			out = append(out, m.DBInstanceIdentifier)
+		}
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CustomEndpoint", func() {
+	members := []ClusterMember{
+		{DBInstanceIdentifier: "writer", IsClusterWrite: true},
+		{DBInstanceIdentifier: "reader-1"},
+		{DBInstanceIdentifier: "reader-2"},
+	}
+
+	It("should create reader endpoints by default", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).Aurora().
+			SetDBClusterIdentifier("foo").
+			SetEndpointIdentifier("analytics").
+			SetStaticMembers([]string{"reader-2"}).(*rdsAurora)
+
+		Expect(aws.ToString(b.createEndpointParam.EndpointType)).To(Equal("READER"))
+		Expect(aws.ToString(b.createEndpointParam.DBClusterIdentifier)).To(Equal("foo"))
+		Expect(aws.ToString(b.deleteEndpointParam.DBClusterEndpointIdentifier)).To(Equal("analytics"))
+		Expect(b.modifyEndpointParam.StaticMembers).To(Equal([]string{"reader-2"}))
+	})
+
+	It("should resolve the members of static and excluded endpoints", func() {
+		Expect(customEndpointMembers(CustomEndpoint{Type: CustomEndpointTypeReader, StaticMembers: []string{"reader-2", "writer"}}, members)).
+			To(Equal([]string{"reader-2"}))
+		Expect(customEndpointMembers(CustomEndpoint{Type: CustomEndpointTypeAny, ExcludedMembers: []string{"reader-1"}}, members)).
+			To(Equal([]string{"writer", "reader-2"}))
+		Expect(customEndpointMembers(CustomEndpoint{Type: CustomEndpointTypeReader}, members)).
+			To(Equal([]string{"reader-1", "reader-2"}))
+	})
+
+	It("should describe the custom endpoints of the clusters with their members", func() {
+		var actions []string
+		core := serveClient(func(req *http.Request) (int, string) {
+			action := requestAction(req)
+			actions = append(actions, action)
+			if action == "DescribeDBClusterEndpoints" {
+				return http.StatusOK, `<DescribeDBClusterEndpointsResponse><DescribeDBClusterEndpointsResult><DBClusterEndpoints><DBClusterEndpointList>` +
+					`<DBClusterEndpointIdentifier>analytics</DBClusterEndpointIdentifier><Endpoint>analytics.cluster-custom.rds</Endpoint>` +
+					`<CustomEndpointType>READER</CustomEndpointType><StaticMembers><member>reader-1</member></StaticMembers>` +
+					`</DBClusterEndpointList></DBClusterEndpoints></DescribeDBClusterEndpointsResult></DescribeDBClusterEndpointsResponse>`
+			}
+			return http.StatusOK, `<DescribeDBClustersResponse><DescribeDBClustersResult><DBClusters><DBCluster>` +
+				`<DBClusterIdentifier>shop</DBClusterIdentifier><CustomEndpoints><member>analytics.cluster-custom.rds</member></CustomEndpoints>` +
+				`<DBClusterMembers><DBClusterMember><DBInstanceIdentifier>writer</DBInstanceIdentifier><IsClusterWriter>true</IsClusterWriter></DBClusterMember>` +
+				`<DBClusterMember><DBInstanceIdentifier>reader-1</DBInstanceIdentifier></DBClusterMember></DBClusterMembers>` +
+				`</DBCluster><DBCluster><DBClusterIdentifier>blog</DBClusterIdentifier></DBCluster></DBClusters></DescribeDBClustersResult></DescribeDBClustersResponse>`
+		})
+
+		a := newAurora(core)
+		a.SetDBClusterIdentifier("shop")
+		desc, err := a.Describe(context.Background())
+		Expect(err).To(BeNil())
+		Expect(desc.CustomEndpoints).To(Equal([]string{"analytics.cluster-custom.rds"}))
+		Expect(desc.CustomEndpointDetails).To(HaveLen(1))
+		Expect(desc.CustomEndpointDetails[0].Identifier).To(Equal("analytics"))
+		Expect(desc.CustomEndpointDetails[0].Type).To(Equal(CustomEndpointTypeReader))
+		Expect(desc.CustomEndpointDetails[0].StaticMembers).To(Equal([]string{"reader-1"}))
+		Expect(desc.CustomEndpointDetails[0].Members).To(Equal([]string{"reader-1"}))
+
+		endpoints, err := a.DescribeReadonlyEndpoints(context.Background())
+		Expect(err).To(BeNil())
+		Expect(endpoints).To(Equal(desc.CustomEndpointDetails))
+
+		actions = nil
+		descs, err := newAurora(core).List(context.Background())
+		Expect(err).To(BeNil())
+		Expect(descs).To(HaveLen(2))
+		Expect(descs[0].CustomEndpointDetails).To(Equal(desc.CustomEndpointDetails))
+		Expect(descs[1].CustomEndpointDetails).To(BeNil())
+		Expect(actions).To(Equal([]string{"DescribeDBClusters", "DescribeDBClusterEndpoints"}))
+	})
+})
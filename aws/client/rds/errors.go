@@ -0,0 +1,126 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+)
+
+var (
+	ErrNotFound      = errors.New("resource not found")
+	ErrAlreadyExists = errors.New("resource already exists")
+	ErrInvalidState  = errors.New("resource is in an invalid state")
+	ErrThrottled     = errors.New("request throttled")
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+var throttleCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"RequestLimitExceeded":                   true,
+	"ProvisionedThroughputExceededException": true,
+}
+
+// Error is an RDS API error together with its classification. Kind is one of
+// the ErrXxx sentinels, or nil when the error matches none of them. The
+// original error, such as *types.DBInstanceNotFoundFault, stays reachable
+// with errors.As.
+type Error struct {
+	Operation string
+	Code      string
+	Kind      error
+	Err       error
+}
+
+func (e *Error) Error() string {
+	if e.Kind != nil {
+		return fmt.Sprintf("rds %s: %s: %s", e.Operation, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("rds %s: %s", e.Operation, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// IsRetryable reports whether the call which returned err may succeed when
+// issued again later: throttling, invalid transient states and the transport
+// errors the SDK retries by itself.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrThrottled) || errors.Is(err, ErrInvalidState) {
+		return true
+	}
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// wrapError classifies err returned by the rds client. It never panics on
+// errors which are not smithy errors, such as context cancellation.
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	e = &Error{Err: err}
+	var opErr *smithy.OperationError
+	if errors.As(err, &opErr) {
+		e.Operation = opErr.Operation()
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		e.Code = apiErr.ErrorCode()
+		e.Kind = classifyErrorCode(e.Code)
+	}
+	if e.Operation == "" && e.Code == "" {
+		return err
+	}
+	return e
+}
+
+func classifyErrorCode(code string) error {
+	switch {
+	case throttleCodes[code]:
+		return ErrThrottled
+	case strings.Contains(code, "NotFound"):
+		return ErrNotFound
+	case strings.Contains(code, "AlreadyExist"):
+		return ErrAlreadyExists
+	case strings.Contains(code, "QuotaExceeded"):
+		return ErrQuotaExceeded
+	case strings.HasPrefix(code, "Invalid") && strings.Contains(code, "State"):
+		return ErrInvalidState
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func operationError(op string, err error) error {
+	return &smithy.OperationError{ServiceID: "RDS", OperationName: op, Err: err}
+}
+
+var _ = Describe("Errors", func() {
+	It("should classify not found faults", func() {
+		err := wrapError(operationError("DeleteDBInstance", &types.DBInstanceNotFoundFault{Message: aws.String("foo")}))
+		Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+		Expect(errors.Is(err, ErrAlreadyExists)).To(BeFalse())
+
+		var fault *types.DBInstanceNotFoundFault
+		Expect(errors.As(err, &fault)).To(BeTrue())
+
+		var e *Error
+		Expect(errors.As(err, &e)).To(BeTrue())
+		Expect(e.Operation).To(Equal("DeleteDBInstance"))
+		Expect(e.Code).To(Equal("DBInstanceNotFound"))
+		Expect(IsRetryable(err)).To(BeFalse())
+	})
+
+	It("should classify already exists, invalid state and quota faults", func() {
+		Expect(errors.Is(wrapError(operationError("CreateDBCluster", &types.DBClusterAlreadyExistsFault{})), ErrAlreadyExists)).To(BeTrue())
+		Expect(errors.Is(wrapError(operationError("RebootDBInstance", &types.InvalidDBInstanceStateFault{})), ErrInvalidState)).To(BeTrue())
+		Expect(errors.Is(wrapError(operationError("CreateDBInstance", &types.InstanceQuotaExceededFault{})), ErrQuotaExceeded)).To(BeTrue())
+		Expect(IsRetryable(wrapError(operationError("RebootDBInstance", &types.InvalidDBInstanceStateFault{})))).To(BeTrue())
+	})
+
+	It("should classify throttling as retryable", func() {
+		err := wrapError(operationError("DescribeDBInstances", &smithy.GenericAPIError{Code: "Throttling"}))
+		Expect(errors.Is(err, ErrThrottled)).To(BeTrue())
+		Expect(IsRetryable(err)).To(BeTrue())
+	})
+
+	It("should not panic on errors which are not operation errors", func() {
+		Expect(wrapError(nil)).To(BeNil())
+		err := wrapError(context.Canceled)
+		Expect(err).To(Equal(context.Canceled))
+		Expect(errors.Is(err, ErrNotFound)).To(BeFalse())
+		Expect(IsRetryable(err)).To(BeFalse())
+	})
+})
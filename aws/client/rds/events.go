@@ -0,0 +1,143 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	EventCategoryAvailability  = "availability"
+	EventCategoryFailover      = "failover"
+	EventCategoryFailure       = "failure"
+	EventCategoryMaintenance   = "maintenance"
+	EventCategoryNotification  = "notification"
+	EventCategoryRecovery      = "recovery"
+	EventCategoryRestoration   = "restoration"
+	EventCategoryBackup        = "backup"
+	EventCategoryConfiguration = "configuration change"
+)
+
+// eventRetentionMinutes is the 14 days rds keeps the events for.
+const eventRetentionMinutes = 14 * 24 * 60
+
+// Event is an event of rds, which keeps the events of the last 14 days.
+type Event struct {
+	SourceIdentifier string
+	SourceArn        string
+	SourceType       string
+	Categories       []string
+	Message          string
+	Date             time.Time
+}
+
+// HasCategory reports whether the event is of category, such as EventCategoryFailover.
+func (e *Event) HasCategory(category string) bool {
+	for _, c := range e.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Events returns the events of the instance sourceID, or of every instance
+// when it is empty, since since or over the last 14 days when since is zero,
+// oldest first.
+func (s *rdsInstance) Events(ctx context.Context, sourceID string, since time.Time) ([]*Event, error) {
+	return describeEvents(ctx, s.core, types.SourceTypeDbInstance, sourceID, since)
+}
+
+// Events returns the events of the cluster sourceID, or of every cluster
+// when it is empty, since since or over the last 14 days when since is zero,
+// oldest first.
+func (s *rdsCluster) Events(ctx context.Context, sourceID string, since time.Time) ([]*Event, error) {
+	return describeEvents(ctx, s.core, types.SourceTypeDbCluster, sourceID, since)
+}
+
+// Events returns the events of the cluster sourceID and of its instances, or
+// of every cluster when it is empty, since since or over the last 14 days
+// when since is zero, oldest first.
+func (s *rdsAurora) Events(ctx context.Context, sourceID string, since time.Time) ([]*Event, error) {
+	events, err := describeEvents(ctx, s.core, types.SourceTypeDbCluster, sourceID, since)
+	if err != nil || sourceID == "" {
+		return events, err
+	}
+
+	instances, err := collect(func(fn func(*DescInstance) bool) error {
+		return iterateInstances(ctx, s.core, &rds.DescribeDBInstancesInput{
+			Filters: setFilter(nil, "db-cluster-id", []string{sourceID}),
+		}, nil, 0, fn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ins := range instances {
+		insEvents, err := describeEvents(ctx, s.core, types.SourceTypeDbInstance, ins.DBInstanceIdentifier, since)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, insEvents...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+	return events, nil
+}
+
+func describeEvents(ctx context.Context, core *rds.Client, sourceType types.SourceType, sourceID string, since time.Time) ([]*Event, error) {
+	in := &rds.DescribeEventsInput{SourceType: sourceType}
+	if sourceID != "" {
+		in.SourceIdentifier = aws.String(sourceID)
+	}
+	if since.IsZero() {
+		// DescribeEvents defaults to the last hour only.
+		in.Duration = aws.Int32(eventRetentionMinutes)
+	} else {
+		in.StartTime = aws.Time(since)
+	}
+	return collect(func(fn func(*Event) bool) error {
+		return paginate(ctx, 0, func(ctx context.Context, marker *string, pageSize int32) ([]*Event, *string, error) {
+			param := *in
+			param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+			out, err := core.DescribeEvents(ctx, &param)
+			if err != nil {
+				return nil, nil, err
+			}
+			events := make([]*Event, 0, len(out.Events))
+			for i := range out.Events {
+				events = append(events, convertEvent(&out.Events[i]))
+			}
+			return events, out.Marker, nil
+		}, fn)
+	})
+}
+
+func convertEvent(in *types.Event) *Event {
+	return &Event{
+		SourceIdentifier: aws.ToString(in.SourceIdentifier),
+		SourceArn:        aws.ToString(in.SourceArn),
+		SourceType:       string(in.SourceType),
+		Categories:       in.EventCategories,
+		Message:          aws.ToString(in.Message),
+		Date:             aws.ToTime(in.Date),
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Events", func() {
+	It("should convert the events with their categories", func() {
+		date := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+		e := convertEvent(&types.Event{
+			SourceIdentifier: aws.String("test-instance"),
+			SourceArn:        aws.String("arn:aws:rds:us-east-1:123456789012:db:test-instance"),
+			SourceType:       types.SourceTypeDbInstance,
+			EventCategories:  []string{EventCategoryFailover, EventCategoryNotification},
+			Message:          aws.String("Multi-AZ instance failover completed"),
+			Date:             aws.Time(date),
+		})
+		Expect(e.SourceIdentifier).To(Equal("test-instance"))
+		Expect(e.SourceType).To(Equal("db-instance"))
+		Expect(e.Message).To(Equal("Multi-AZ instance failover completed"))
+		Expect(e.Date).To(Equal(date))
+		Expect(e.HasCategory(EventCategoryFailover)).To(BeTrue())
+		Expect(e.HasCategory(EventCategoryBackup)).To(BeFalse())
+	})
+
+	It("should ask for the 14 days of retention unless since is set", func() {
+		var forms []url.Values
+		core := serveClient(func(req *http.Request) (int, string) {
+			body, _ := io.ReadAll(req.Body)
+			form, _ := url.ParseQuery(string(body))
+			forms = append(forms, form)
+			return http.StatusOK, `<DescribeEventsResponse><DescribeEventsResult><Events></Events></DescribeEventsResult></DescribeEventsResponse>`
+		})
+		_, err := newInstance(core).Events(context.Background(), "test-instance", time.Time{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(forms[0].Get("Duration")).To(Equal("20160"))
+		Expect(forms[0].Get("StartTime")).To(BeEmpty())
+
+		_, err = newInstance(core).Events(context.Background(), "test-instance", time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(forms[1].Get("Duration")).To(BeEmpty())
+		Expect(forms[1].Get("StartTime")).To(Equal("2023-05-01T10:00:00Z"))
+	})
+})
@@ -0,0 +1,286 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/database-mesh/golang-sdk/aws/client/s3"
+)
+
+type ExportTaskStatus string
+
+const (
+	ExportTaskStatusStarting   ExportTaskStatus = "STARTING"
+	ExportTaskStatusInProgress ExportTaskStatus = "IN_PROGRESS"
+	ExportTaskStatusComplete   ExportTaskStatus = "COMPLETE"
+	ExportTaskStatusCanceling  ExportTaskStatus = "CANCELING"
+	ExportTaskStatusCanceled   ExportTaskStatus = "CANCELED"
+	ExportTaskStatusFailed     ExportTaskStatus = "FAILED"
+)
+
+// ErrExportOutput is matched by the error of VerifyOutput when the export
+// output is missing from the bucket.
+var ErrExportOutput = errors.New("export output is incomplete")
+
+type DescExportTask struct {
+	ExportTaskIdentifier   string
+	SourceArn              string
+	SourceType             string
+	Status                 ExportTaskStatus
+	S3Bucket               string
+	S3Prefix               string
+	IamRoleArn             string
+	KmsKeyId               string
+	ExportOnly             []string
+	PercentProgress        int32
+	TotalExtractedDataInGB int32
+	SnapshotTime           time.Time
+	TaskStartTime          time.Time
+	TaskEndTime            time.Time
+	FailureCause           string
+	WarningMessage         string
+}
+
+// ExportOutput lists the objects written by an export task.
+type ExportOutput struct {
+	// Prefix is <S3Prefix>/<ExportTaskIdentifier>/, under which the task writes.
+	Prefix       string
+	InfoFiles    []string
+	ParquetFiles []string
+}
+
+// ExportTask exports snapshots to S3 as Parquet.
+type ExportTask interface {
+	SetExportTaskIdentifier(id string) ExportTask
+	SetSourceArn(arn string) ExportTask
+	SetS3BucketName(bucket string) ExportTask
+	SetS3Prefix(prefix string) ExportTask
+	SetIamRoleArn(arn string) ExportTask
+	SetKmsKeyId(id string) ExportTask
+	SetExportOnly(names []string) ExportTask
+	SetMaxResults(max int32) ExportTask
+
+	Start(context.Context) error
+	Cancel(context.Context) error
+	Describe(context.Context) (*DescExportTask, error)
+	List(context.Context) ([]*DescExportTask, error)
+	WaitFor(ctx context.Context, status ExportTaskStatus, opts *WaitOptions) error
+	// VerifyOutput lists the output of the task with objects, which is an
+	// s3.Object of the region of the bucket.
+	VerifyOutput(ctx context.Context, objects s3.Object) (*ExportOutput, error)
+}
+
+type rdsExportTask struct {
+	core *rds.Client
+
+	startExportTaskParam     *rds.StartExportTaskInput
+	cancelExportTaskParam    *rds.CancelExportTaskInput
+	describeExportTasksParam *rds.DescribeExportTasksInput
+
+	maxResults int32
+}
+
+func newExportTask(core *rds.Client) *rdsExportTask {
+	return &rdsExportTask{
+		core:                     core,
+		startExportTaskParam:     &rds.StartExportTaskInput{},
+		cancelExportTaskParam:    &rds.CancelExportTaskInput{},
+		describeExportTasksParam: &rds.DescribeExportTasksInput{},
+	}
+}
+
+func (s *rdsExportTask) SetExportTaskIdentifier(id string) ExportTask {
+	s.startExportTaskParam.ExportTaskIdentifier = aws.String(id)
+	s.cancelExportTaskParam.ExportTaskIdentifier = aws.String(id)
+	s.describeExportTasksParam.ExportTaskIdentifier = aws.String(id)
+	return s
+}
+
+// SetSourceArn sets the ARN of the snapshot to export.
+func (s *rdsExportTask) SetSourceArn(arn string) ExportTask {
+	s.startExportTaskParam.SourceArn = aws.String(arn)
+	s.describeExportTasksParam.SourceArn = aws.String(arn)
+	return s
+}
+
+func (s *rdsExportTask) SetS3BucketName(bucket string) ExportTask {
+	s.startExportTaskParam.S3BucketName = aws.String(bucket)
+	return s
+}
+
+func (s *rdsExportTask) SetS3Prefix(prefix string) ExportTask {
+	s.startExportTaskParam.S3Prefix = aws.String(strings.Trim(prefix, "/"))
+	return s
+}
+
+// SetIamRoleArn sets the role used by rds to write to the bucket.
+func (s *rdsExportTask) SetIamRoleArn(arn string) ExportTask {
+	s.startExportTaskParam.IamRoleArn = aws.String(arn)
+	return s
+}
+
+// SetKmsKeyId sets the key encrypting the exported data.
+func (s *rdsExportTask) SetKmsKeyId(id string) ExportTask {
+	s.startExportTaskParam.KmsKeyId = aws.String(id)
+	return s
+}
+
+// SetExportOnly restricts the export to databases, schemas or tables, such as database.schema.table.
+func (s *rdsExportTask) SetExportOnly(names []string) ExportTask {
+	s.startExportTaskParam.ExportOnly = names
+	return s
+}
+
+// SetMaxResults caps the number of tasks returned by List, 0 for no cap.
+func (s *rdsExportTask) SetMaxResults(max int32) ExportTask {
+	s.maxResults = max
+	return s
+}
+
+func (s *rdsExportTask) Start(ctx context.Context) error {
+	_, err := s.core.StartExportTask(ctx, s.startExportTaskParam)
+	return wrapError(err)
+}
+
+func (s *rdsExportTask) Cancel(ctx context.Context) error {
+	_, err := s.core.CancelExportTask(ctx, s.cancelExportTaskParam)
+	return wrapError(err)
+}
+
+// Describe returns the task set by SetExportTaskIdentifier, nil when it does not exist.
+func (s *rdsExportTask) Describe(ctx context.Context) (*DescExportTask, error) {
+	if s.describeExportTasksParam.ExportTaskIdentifier == nil {
+		return nil, errors.New("export task identifier is required")
+	}
+	tasks, err := s.List(ctx)
+	if err != nil || len(tasks) == 0 {
+		return nil, err
+	}
+	return tasks[0], nil
+}
+
+// List returns the tasks matching the identifier and the source ARN.
+func (s *rdsExportTask) List(ctx context.Context) ([]*DescExportTask, error) {
+	return collect(func(fn func(*DescExportTask) bool) error {
+		return ignoreNotFound(paginate(ctx, s.maxResults, func(ctx context.Context, marker *string, pageSize int32) ([]*DescExportTask, *string, error) {
+			param := *s.describeExportTasksParam
+			param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+			out, err := s.core.DescribeExportTasks(ctx, &param)
+			if err != nil {
+				return nil, nil, err
+			}
+			var descs []*DescExportTask
+			for i := range out.ExportTasks {
+				descs = append(descs, convertExportTask(&out.ExportTasks[i]))
+			}
+			return descs, out.Marker, nil
+		}, fn))
+	})
+}
+
+// WaitFor waits until the task reaches status.
+func (s *rdsExportTask) WaitFor(ctx context.Context, status ExportTaskStatus, opts *WaitOptions) error {
+	id := s.describeExportTasksParam.ExportTaskIdentifier
+	if id == nil {
+		return errors.New("export task identifier is required")
+	}
+	return waitFor(ctx, *id, string(status), opts, func(ctx context.Context) (string, error) {
+		desc, err := s.Describe(ctx)
+		if err != nil || desc == nil {
+			return "", err
+		}
+		return string(desc.Status), nil
+	}, func(current string) bool {
+		switch ExportTaskStatus(current) {
+		case ExportTaskStatusComplete, ExportTaskStatusCanceled, ExportTaskStatusFailed:
+			return true
+		}
+		return false
+	})
+}
+
+func (s *rdsExportTask) VerifyOutput(ctx context.Context, objects s3.Object) (*ExportOutput, error) {
+	desc, err := s.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, fmt.Errorf("export task %s: %w", aws.ToString(s.describeExportTasksParam.ExportTaskIdentifier), ErrExportOutput)
+	}
+
+	prefix := exportOutputPrefix(desc.S3Prefix, desc.ExportTaskIdentifier)
+	keys, err := objects.SetBucket(desc.S3Bucket).SetPrefix(prefix).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output := classifyExportOutput(prefix, keys)
+	if len(output.InfoFiles) == 0 {
+		return output, fmt.Errorf("no export info file under s3://%s/%s: %w", desc.S3Bucket, prefix, ErrExportOutput)
+	}
+	if desc.Status == ExportTaskStatusComplete && desc.TotalExtractedDataInGB > 0 && len(output.ParquetFiles) == 0 {
+		return output, fmt.Errorf("no parquet file under s3://%s/%s: %w", desc.S3Bucket, prefix, ErrExportOutput)
+	}
+	return output, nil
+}
+
+func exportOutputPrefix(prefix, id string) string {
+	return strings.TrimPrefix(path.Join(prefix, id), "/") + "/"
+}
+
+// classifyExportOutput sorts the keys written by a task into its export_info
+// json files and its parquet data files.
+func classifyExportOutput(prefix string, keys []string) *ExportOutput {
+	output := &ExportOutput{Prefix: prefix}
+	for _, key := range keys {
+		name := path.Base(key)
+		switch {
+		case strings.HasPrefix(name, "export_") && strings.HasSuffix(name, ".json"):
+			output.InfoFiles = append(output.InfoFiles, key)
+		case strings.HasSuffix(name, ".parquet"):
+			output.ParquetFiles = append(output.ParquetFiles, key)
+		}
+	}
+	return output
+}
+
+func convertExportTask(in *types.ExportTask) *DescExportTask {
+	return &DescExportTask{
+		ExportTaskIdentifier:   aws.ToString(in.ExportTaskIdentifier),
+		SourceArn:              aws.ToString(in.SourceArn),
+		SourceType:             string(in.SourceType),
+		Status:                 ExportTaskStatus(aws.ToString(in.Status)),
+		S3Bucket:               aws.ToString(in.S3Bucket),
+		S3Prefix:               aws.ToString(in.S3Prefix),
+		IamRoleArn:             aws.ToString(in.IamRoleArn),
+		KmsKeyId:               aws.ToString(in.KmsKeyId),
+		ExportOnly:             in.ExportOnly,
+		PercentProgress:        in.PercentProgress,
+		TotalExtractedDataInGB: in.TotalExtractedDataInGB,
+		SnapshotTime:           aws.ToTime(in.SnapshotTime),
+		TaskStartTime:          aws.ToTime(in.TaskStartTime),
+		TaskEndTime:            aws.ToTime(in.TaskEndTime),
+		FailureCause:           aws.ToString(in.FailureCause),
+		WarningMessage:         aws.ToString(in.WarningMessage),
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExportTask", func() {
+	It("should feed the export inputs from the setters", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).ExportTask().
+			SetExportTaskIdentifier("audit-2023").
+			SetSourceArn("arn:aws:rds:us-east-1:123456789012:snapshot:foo").
+			SetS3BucketName("audit").
+			SetS3Prefix("/exports/").(*rdsExportTask)
+
+		Expect(aws.ToString(b.startExportTaskParam.S3Prefix)).To(Equal("exports"))
+		Expect(aws.ToString(b.cancelExportTaskParam.ExportTaskIdentifier)).To(Equal("audit-2023"))
+		Expect(aws.ToString(b.describeExportTasksParam.SourceArn)).To(HaveSuffix(":snapshot:foo"))
+	})
+
+	It("should locate and classify the export output", func() {
+		Expect(exportOutputPrefix("exports", "audit-2023")).To(Equal("exports/audit-2023/"))
+		Expect(exportOutputPrefix("", "audit-2023")).To(Equal("audit-2023/"))
+
+		output := classifyExportOutput("exports/audit-2023/", []string{
+			"exports/audit-2023/export_info_audit-2023.json",
+			"exports/audit-2023/export_tables_info_audit-2023_from_1_to_2.json",
+			"exports/audit-2023/shop/shop.orders/1/part-00000-1.gz.parquet",
+			"exports/audit-2023/shop/shop.orders/1/_SUCCESS",
+		})
+		Expect(output.InfoFiles).To(HaveLen(2))
+		Expect(output.ParquetFiles).To(Equal([]string{"exports/audit-2023/shop/shop.orders/1/part-00000-1.gz.parquet"}))
+	})
+})
@@ -0,0 +1,138 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// ErrNoReader is returned by FailoverRandomOneReadonlyEndpoint when no
+// available reader matches the filters.
+var ErrNoReader = errors.New("no available reader matches")
+
+type ReaderFailoverMode string
+
+const (
+	// ReaderFailoverModeReboot reboots the reader, the writer is not touched.
+	ReaderFailoverModeReboot ReaderFailoverMode = "reboot"
+	// ReaderFailoverModePromote fails the cluster over to the reader, which
+	// becomes the writer while the former writer becomes a reader.
+	ReaderFailoverModePromote ReaderFailoverMode = "promote"
+)
+
+type readerFailover struct {
+	mode     ReaderFailoverMode
+	az       string
+	tagKey   string
+	tagValue string
+	intn     func(n int) int
+}
+
+// SetReaderFailoverMode sets how FailoverRandomOneReadonlyEndpoint affects the
+// reader, ReaderFailoverModeReboot by default.
+func (s *rdsAurora) SetReaderFailoverMode(mode ReaderFailoverMode) Aurora {
+	s.readerFailover.mode = mode
+	return s
+}
+
+// SetReaderAvailabilityZone restricts FailoverRandomOneReadonlyEndpoint to the readers in az.
+func (s *rdsAurora) SetReaderAvailabilityZone(az string) Aurora {
+	s.readerFailover.az = az
+	return s
+}
+
+// SetReaderTag restricts FailoverRandomOneReadonlyEndpoint to the readers
+// tagged key, with value unless value is empty.
+func (s *rdsAurora) SetReaderTag(key, value string) Aurora {
+	s.readerFailover.tagKey = key
+	s.readerFailover.tagValue = value
+	return s
+}
+
+// FailoverRandomOneReadonlyEndpoint picks a random available reader of the
+// cluster, filtered by SetReaderAvailabilityZone and SetReaderTag, and reboots
+// it or fails the cluster over to it according to SetReaderFailoverMode. It
+// returns the affected reader, or ErrNoReader when none matches.
+func (s *rdsAurora) FailoverRandomOneReadonlyEndpoint(ctx context.Context) (*DescInstance, error) {
+	desc, err := s.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, fmt.Errorf("db cluster %s: %w", aws.ToString(s.describeClusterParam.DBClusterIdentifier), ErrNotFound)
+	}
+
+	instances, err := s.describeInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	intn := s.readerFailover.intn
+	if intn == nil {
+		intn = rand.New(rand.NewSource(time.Now().UnixNano())).Intn
+	}
+	reader := pickReader(desc.DBClusterMembers, instances, s.readerFailover, intn)
+	if reader == nil {
+		return nil, ErrNoReader
+	}
+
+	if s.readerFailover.mode == ReaderFailoverModePromote {
+		_, err = s.core.FailoverDBCluster(ctx, &rds.FailoverDBClusterInput{
+			DBClusterIdentifier:        aws.String(desc.DBClusterIdentifier),
+			TargetDBInstanceIdentifier: aws.String(reader.DBInstanceIdentifier),
+		})
+	} else {
+		_, err = s.core.RebootDBInstance(ctx, &rds.RebootDBInstanceInput{
+			DBInstanceIdentifier: aws.String(reader.DBInstanceIdentifier),
+		})
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return reader, nil
+}
+
+// pickReader returns a random available instance among the non writer
+// members matching the filters of f.
+func pickReader(members []ClusterMember, instances []*DescInstance, f readerFailover, intn func(int) int) *DescInstance {
+	writers := map[string]bool{}
+	for _, m := range members {
+		writers[m.DBInstanceIdentifier] = m.IsClusterWrite
+	}
+
+	var candidates []*DescInstance
+	for _, ins := range instances {
+		isWriter, isMember := writers[ins.DBInstanceIdentifier]
+		switch {
+		case !isMember || isWriter:
+		case ins.DBInstanceStatus != DBInstanceStatusAvailable:
+		case f.az != "" && ins.AvailabilityZone != f.az:
+		case f.tagKey != "" && !matchTag(ins.Tags, f.tagKey, f.tagValue):
+		default:
+			candidates = append(candidates, ins)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[intn(len(candidates))]
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Failover", func() {
+	members := []ClusterMember{
+		{DBInstanceIdentifier: "writer", IsClusterWrite: true},
+		{DBInstanceIdentifier: "reader-a"},
+		{DBInstanceIdentifier: "reader-b"},
+		{DBInstanceIdentifier: "reader-c"},
+	}
+	instances := []*DescInstance{
+		{DBInstanceIdentifier: "writer", DBInstanceStatus: DBInstanceStatusAvailable, AvailabilityZone: "us-east-1a"},
+		{DBInstanceIdentifier: "reader-a", DBInstanceStatus: DBInstanceStatusAvailable, AvailabilityZone: "us-east-1a", Tags: map[string]string{"role": "analytics"}},
+		{DBInstanceIdentifier: "reader-b", DBInstanceStatus: DBInstanceStatusAvailable, AvailabilityZone: "us-east-1b"},
+		{DBInstanceIdentifier: "reader-c", DBInstanceStatus: DBInstanceStatusRebooting, AvailabilityZone: "us-east-1b"},
+	}
+	last := func(n int) int { return n - 1 }
+
+	It("should never pick the writer or an unavailable reader", func() {
+		var picked []string
+		for i := 0; i < 2; i++ {
+			r := pickReader(members, instances, readerFailover{}, func(int) int { return i })
+			picked = append(picked, r.DBInstanceIdentifier)
+		}
+		Expect(picked).To(Equal([]string{"reader-a", "reader-b"}))
+	})
+
+	It("should filter the readers by availability zone and tag", func() {
+		Expect(pickReader(members, instances, readerFailover{az: "us-east-1b"}, last).DBInstanceIdentifier).To(Equal("reader-b"))
+		Expect(pickReader(members, instances, readerFailover{tagKey: "role", tagValue: "analytics"}, last).DBInstanceIdentifier).To(Equal("reader-a"))
+		Expect(pickReader(members, instances, readerFailover{az: "us-east-1c"}, last)).To(BeNil())
+	})
+})
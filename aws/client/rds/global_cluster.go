@@ -0,0 +1,346 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	sdkaws "github.com/database-mesh/golang-sdk/aws"
+)
+
+const (
+	GlobalClusterStatusAvailable   = "available"
+	GlobalClusterStatusFailingOver = "failing-over"
+	GlobalClusterStatusModifying   = "modifying"
+	GlobalClusterStatusDeleting    = "deleting"
+)
+
+// ErrDataLossNotAllowed is returned by Failover unless SetAllowDataLoss is set.
+var ErrDataLossNotAllowed = errors.New("failover may lose data, allow it with SetAllowDataLoss or use Switchover")
+
+type DescGlobalCluster struct {
+	GlobalClusterIdentifier string
+	GlobalClusterArn        string
+	GlobalClusterResourceId string
+	Engine                  string
+	EngineVersion           string
+	DatabaseName            string
+	Status                  string
+	DeletionProtection      bool
+	StorageEncrypted        bool
+	Members                 []GlobalClusterMember
+	// FailoverState is set while a switchover is in progress.
+	FailoverState *GlobalFailoverState
+}
+
+type GlobalClusterMember struct {
+	DBClusterArn          string
+	Region                string
+	IsWriter              bool
+	Readers               []string
+	WriteForwardingStatus string
+}
+
+type GlobalFailoverState struct {
+	FromDBClusterArn string
+	ToDBClusterArn   string
+	Status           string
+}
+
+// Writer returns the primary cluster of the global cluster, nil when there is none.
+func (d *DescGlobalCluster) Writer() *GlobalClusterMember {
+	for i := range d.Members {
+		if d.Members[i].IsWriter {
+			return &d.Members[i]
+		}
+	}
+	return nil
+}
+
+// GlobalCluster manages an Aurora global database, whose clusters live in
+// different regions. The builder is created in the region of the primary
+// cluster, except for Failover.
+type GlobalCluster interface {
+	SetGlobalClusterIdentifier(id string) GlobalCluster
+	SetSourceDBClusterIdentifier(arn string) GlobalCluster
+	SetEngine(engine string) GlobalCluster
+	SetEngineVersion(version string) GlobalCluster
+	SetDatabaseName(name string) GlobalCluster
+	SetDeletionProtection(enable bool) GlobalCluster
+	SetStorageEncrypted(enable bool) GlobalCluster
+	SetDBClusterIdentifier(arn string) GlobalCluster
+	SetAllowDataLoss(enable bool) GlobalCluster
+	SetMaxResults(max int32) GlobalCluster
+
+	Create(context.Context) error
+	Delete(context.Context) error
+	Describe(context.Context) (*DescGlobalCluster, error)
+	List(context.Context) ([]*DescGlobalCluster, error)
+	// AddMember creates secondary, an Aurora built in the region of the
+	// secondary cluster, as a member of the global cluster.
+	AddMember(ctx context.Context, secondary Aurora) error
+	// AddSecondaryRegion creates a secondary cluster in region with the
+	// session of sessions for it. configure sets the cluster identifier, the
+	// subnet group and the instances of the secondary cluster.
+	AddSecondaryRegion(ctx context.Context, sessions sdkaws.Sessions, region string, configure func(Aurora) Aurora) error
+	// RemoveMember detaches the cluster set by SetDBClusterIdentifier, which
+	// becomes a standalone cluster with its own writer.
+	RemoveMember(context.Context) error
+	// Switchover makes the cluster set by SetDBClusterIdentifier the primary
+	// cluster once the secondary clusters caught up, without data loss. The
+	// current primary cluster must be available.
+	Switchover(context.Context) error
+	// Failover promotes the cluster set by SetDBClusterIdentifier when the
+	// primary region is unavailable, by detaching it from the global
+	// cluster. The writes not yet replicated to it are lost, so Failover
+	// requires SetAllowDataLoss, and the builder must be created in the
+	// region of the promoted cluster.
+	Failover(context.Context) error
+	WaitFor(ctx context.Context, status string, opts *WaitOptions) error
+}
+
+type rdsGlobalCluster struct {
+	core *rds.Client
+
+	createGlobalClusterParam     *rds.CreateGlobalClusterInput
+	deleteGlobalClusterParam     *rds.DeleteGlobalClusterInput
+	describeGlobalClustersParam  *rds.DescribeGlobalClustersInput
+	removeFromGlobalClusterParam *rds.RemoveFromGlobalClusterInput
+	failoverGlobalClusterParam   *rds.FailoverGlobalClusterInput
+
+	allowDataLoss bool
+	maxResults    int32
+}
+
+func newGlobalCluster(core *rds.Client) *rdsGlobalCluster {
+	return &rdsGlobalCluster{
+		core:                         core,
+		createGlobalClusterParam:     &rds.CreateGlobalClusterInput{},
+		deleteGlobalClusterParam:     &rds.DeleteGlobalClusterInput{},
+		describeGlobalClustersParam:  &rds.DescribeGlobalClustersInput{},
+		removeFromGlobalClusterParam: &rds.RemoveFromGlobalClusterInput{},
+		failoverGlobalClusterParam:   &rds.FailoverGlobalClusterInput{},
+	}
+}
+
+func (s *rdsGlobalCluster) SetGlobalClusterIdentifier(id string) GlobalCluster {
+	s.createGlobalClusterParam.GlobalClusterIdentifier = aws.String(id)
+	s.deleteGlobalClusterParam.GlobalClusterIdentifier = aws.String(id)
+	s.describeGlobalClustersParam.GlobalClusterIdentifier = aws.String(id)
+	s.removeFromGlobalClusterParam.GlobalClusterIdentifier = aws.String(id)
+	s.failoverGlobalClusterParam.GlobalClusterIdentifier = aws.String(id)
+	return s
+}
+
+// SetSourceDBClusterIdentifier sets the ARN of an existing cluster, which
+// becomes the primary cluster of the global cluster made by Create.
+func (s *rdsGlobalCluster) SetSourceDBClusterIdentifier(arn string) GlobalCluster {
+	s.createGlobalClusterParam.SourceDBClusterIdentifier = aws.String(arn)
+	return s
+}
+
+func (s *rdsGlobalCluster) SetEngine(engine string) GlobalCluster {
+	s.createGlobalClusterParam.Engine = aws.String(engine)
+	return s
+}
+
+func (s *rdsGlobalCluster) SetEngineVersion(version string) GlobalCluster {
+	s.createGlobalClusterParam.EngineVersion = aws.String(version)
+	return s
+}
+
+func (s *rdsGlobalCluster) SetDatabaseName(name string) GlobalCluster {
+	s.createGlobalClusterParam.DatabaseName = aws.String(name)
+	return s
+}
+
+func (s *rdsGlobalCluster) SetDeletionProtection(enable bool) GlobalCluster {
+	s.createGlobalClusterParam.DeletionProtection = aws.Bool(enable)
+	return s
+}
+
+func (s *rdsGlobalCluster) SetStorageEncrypted(enable bool) GlobalCluster {
+	s.createGlobalClusterParam.StorageEncrypted = aws.Bool(enable)
+	return s
+}
+
+// SetDBClusterIdentifier sets the ARN of the member removed by RemoveMember,
+// or promoted by Switchover and Failover.
+func (s *rdsGlobalCluster) SetDBClusterIdentifier(arn string) GlobalCluster {
+	s.removeFromGlobalClusterParam.DbClusterIdentifier = aws.String(arn)
+	s.failoverGlobalClusterParam.TargetDbClusterIdentifier = aws.String(arn)
+	return s
+}
+
+func (s *rdsGlobalCluster) SetAllowDataLoss(enable bool) GlobalCluster {
+	s.allowDataLoss = enable
+	return s
+}
+
+// SetMaxResults caps the number of global clusters returned by List, 0 for no cap.
+func (s *rdsGlobalCluster) SetMaxResults(max int32) GlobalCluster {
+	s.maxResults = max
+	return s
+}
+
+func (s *rdsGlobalCluster) Create(ctx context.Context) error {
+	_, err := s.core.CreateGlobalCluster(ctx, s.createGlobalClusterParam)
+	return wrapError(err)
+}
+
+// Delete deletes the global cluster, whose members must be removed before.
+func (s *rdsGlobalCluster) Delete(ctx context.Context) error {
+	_, err := s.core.DeleteGlobalCluster(ctx, s.deleteGlobalClusterParam)
+	return wrapError(err)
+}
+
+// Describe returns the global cluster, nil when it does not exist.
+func (s *rdsGlobalCluster) Describe(ctx context.Context) (*DescGlobalCluster, error) {
+	if s.describeGlobalClustersParam.GlobalClusterIdentifier == nil {
+		return nil, errors.New("global cluster identifier is required")
+	}
+	descs, err := s.List(ctx)
+	if err != nil || len(descs) == 0 {
+		return nil, err
+	}
+	return descs[0], nil
+}
+
+func (s *rdsGlobalCluster) List(ctx context.Context) ([]*DescGlobalCluster, error) {
+	return collect(func(fn func(*DescGlobalCluster) bool) error {
+		return ignoreNotFound(paginate(ctx, s.maxResults, func(ctx context.Context, marker *string, pageSize int32) ([]*DescGlobalCluster, *string, error) {
+			param := *s.describeGlobalClustersParam
+			param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+			out, err := s.core.DescribeGlobalClusters(ctx, &param)
+			if err != nil {
+				return nil, nil, err
+			}
+			var descs []*DescGlobalCluster
+			for i := range out.GlobalClusters {
+				descs = append(descs, convertGlobalCluster(&out.GlobalClusters[i]))
+			}
+			return descs, out.Marker, nil
+		}, fn))
+	})
+}
+
+func (s *rdsGlobalCluster) AddMember(ctx context.Context, secondary Aurora) error {
+	id := s.describeGlobalClustersParam.GlobalClusterIdentifier
+	if id == nil {
+		return errors.New("global cluster identifier is required")
+	}
+	desc, err := s.Describe(ctx)
+	if err != nil {
+		return err
+	}
+	if desc == nil {
+		return fmt.Errorf("global cluster %s: %w", *id, ErrNotFound)
+	}
+	return secondary.
+		SetGlobalClusterIdentifier(desc.GlobalClusterIdentifier).
+		SetEngine(desc.Engine).
+		SetEngineVersion(desc.EngineVersion).
+		Create(ctx)
+}
+
+func (s *rdsGlobalCluster) AddSecondaryRegion(ctx context.Context, sessions sdkaws.Sessions, region string, configure func(Aurora) Aurora) error {
+	sess, ok := sessions[region]
+	if !ok {
+		return fmt.Errorf("no session for region %s", region)
+	}
+	secondary := NewService(sess).Aurora()
+	if configure != nil {
+		secondary = configure(secondary)
+	}
+	return s.AddMember(ctx, secondary)
+}
+
+func (s *rdsGlobalCluster) RemoveMember(ctx context.Context) error {
+	_, err := s.core.RemoveFromGlobalCluster(ctx, s.removeFromGlobalClusterParam)
+	return wrapError(err)
+}
+
+func (s *rdsGlobalCluster) Switchover(ctx context.Context) error {
+	_, err := s.core.FailoverGlobalCluster(ctx, s.failoverGlobalClusterParam)
+	return wrapError(err)
+}
+
+func (s *rdsGlobalCluster) Failover(ctx context.Context) error {
+	if !s.allowDataLoss {
+		return ErrDataLossNotAllowed
+	}
+	return s.RemoveMember(ctx)
+}
+
+// WaitFor waits until the global cluster reaches status.
+func (s *rdsGlobalCluster) WaitFor(ctx context.Context, status string, opts *WaitOptions) error {
+	id := s.describeGlobalClustersParam.GlobalClusterIdentifier
+	if id == nil {
+		return errors.New("global cluster identifier is required")
+	}
+	return waitFor(ctx, *id, status, opts, func(ctx context.Context) (string, error) {
+		desc, err := s.Describe(ctx)
+		if err != nil || desc == nil {
+			return "", err
+		}
+		return desc.Status, nil
+	}, func(current string) bool {
+		return current == GlobalClusterStatusDeleting && status != GlobalClusterStatusDeleting
+	})
+}
+
+// SetGlobalClusterIdentifier makes the cluster created by Create a secondary
+// cluster of the global cluster.
+func (s *rdsAurora) SetGlobalClusterIdentifier(id string) Aurora {
+	s.createClusterParam.GlobalClusterIdentifier = aws.String(id)
+	s.failoverGlobalClusterParam.GlobalClusterIdentifier = aws.String(id)
+	return s
+}
+
+func convertGlobalCluster(in *types.GlobalCluster) *DescGlobalCluster {
+	desc := &DescGlobalCluster{
+		GlobalClusterIdentifier: aws.ToString(in.GlobalClusterIdentifier),
+		GlobalClusterArn:        aws.ToString(in.GlobalClusterArn),
+		GlobalClusterResourceId: aws.ToString(in.GlobalClusterResourceId),
+		Engine:                  aws.ToString(in.Engine),
+		EngineVersion:           aws.ToString(in.EngineVersion),
+		DatabaseName:            aws.ToString(in.DatabaseName),
+		Status:                  aws.ToString(in.Status),
+		DeletionProtection:      aws.ToBool(in.DeletionProtection),
+		StorageEncrypted:        aws.ToBool(in.StorageEncrypted),
+	}
+	for _, m := range in.GlobalClusterMembers {
+		desc.Members = append(desc.Members, GlobalClusterMember{
+			DBClusterArn:          aws.ToString(m.DBClusterArn),
+			Region:                arnRegion(aws.ToString(m.DBClusterArn)),
+			IsWriter:              m.IsWriter,
+			Readers:               m.Readers,
+			WriteForwardingStatus: string(m.GlobalWriteForwardingStatus),
+		})
+	}
+	if in.FailoverState != nil {
+		desc.FailoverState = &GlobalFailoverState{
+			FromDBClusterArn: aws.ToString(in.FailoverState.FromDbClusterArn),
+			ToDBClusterArn:   aws.ToString(in.FailoverState.ToDbClusterArn),
+			Status:           string(in.FailoverState.Status),
+		}
+	}
+	return desc
+}
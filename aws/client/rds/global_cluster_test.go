@@ -0,0 +1,71 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	sdkaws "github.com/database-mesh/golang-sdk/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GlobalCluster", func() {
+	const secondaryArn = "arn:aws:rds:eu-west-1:123456789012:cluster:shop-eu"
+
+	It("should feed the member operations from the setters", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).GlobalCluster().
+			SetGlobalClusterIdentifier("shop").
+			SetDBClusterIdentifier(secondaryArn).(*rdsGlobalCluster)
+
+		Expect(aws.ToString(b.removeFromGlobalClusterParam.GlobalClusterIdentifier)).To(Equal("shop"))
+		Expect(aws.ToString(b.removeFromGlobalClusterParam.DbClusterIdentifier)).To(Equal(secondaryArn))
+		Expect(aws.ToString(b.failoverGlobalClusterParam.TargetDbClusterIdentifier)).To(Equal(secondaryArn))
+
+		a := newAurora(nil)
+		a.SetGlobalClusterIdentifier("shop")
+		Expect(aws.ToString(a.createClusterParam.GlobalClusterIdentifier)).To(Equal("shop"))
+	})
+
+	It("should refuse a failover which may lose data unless allowed", func() {
+		b := newGlobalCluster(nil)
+		b.SetGlobalClusterIdentifier("shop").SetDBClusterIdentifier(secondaryArn)
+		Expect(b.Failover(context.Background())).To(MatchError(ErrDataLossNotAllowed))
+	})
+
+	It("should require a session for the secondary region", func() {
+		b := newGlobalCluster(nil)
+		b.SetGlobalClusterIdentifier("shop")
+		err := b.AddSecondaryRegion(context.Background(), sdkaws.Sessions{"us-east-1": aws.Config{}}, "eu-west-1", nil)
+		Expect(err).To(MatchError(ContainSubstring("eu-west-1")))
+	})
+
+	It("should report the members with their region and the writer", func() {
+		desc := convertGlobalCluster(&types.GlobalCluster{
+			GlobalClusterIdentifier: aws.String("shop"),
+			GlobalClusterMembers: []types.GlobalClusterMember{
+				{DBClusterArn: aws.String("arn:aws:rds:us-east-1:123456789012:cluster:shop-us"), IsWriter: true, Readers: []string{secondaryArn}},
+				{DBClusterArn: aws.String(secondaryArn)},
+			},
+			FailoverState: &types.FailoverState{Status: types.FailoverStatusPending, ToDbClusterArn: aws.String(secondaryArn)},
+		})
+		Expect(desc.Writer().Region).To(Equal("us-east-1"))
+		Expect(desc.Members[1].Region).To(Equal("eu-west-1"))
+		Expect(desc.FailoverState.Status).To(Equal("pending"))
+		Expect((&DescGlobalCluster{}).Writer()).To(BeNil())
+	})
+})
@@ -17,12 +17,12 @@ package rds
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/rds/types"
-	"github.com/aws/smithy-go"
 )
 
 type Instance interface {
@@ -54,17 +54,55 @@ type Instance interface {
 	SetLicenseModel(model string) Instance
 	SetSnapshotIdentifier(id string) Instance
 	SetFilter(name string, values []string) Instance
+	SetSnapshotFilter(name string, values []string) Instance
+	SetMaxResults(max int32) Instance
+	SetTargetSnapshotIdentifier(id string) Instance
+	SetCopyTags(enable bool) Instance
+	SetWatchInterval(interval time.Duration) Instance
+	SetStorageType(t string) Instance
+	SetMaxAllocatedStorage(size int32) Instance
+	SetBackupRetentionPeriod(days int32) Instance
+	SetPreferredMaintenanceWindow(window string) Instance
+	SetDeletionProtection(enable bool) Instance
+	SetDBParameterGroupName(name string) Instance
+	SetOptionGroupName(name string) Instance
+	SetTags(tags map[string]string) Instance
+	SetApplyImmediately(enable bool) Instance
+	SetStopSnapshotIdentifier(id string) Instance
+	SetKmsKeyId(id string) Instance
+	SetSourceRegion(region string) Instance
+	SetPreferredBackupWindow(window string) Instance
 
 	Create(context.Context) error
 	Delete(context.Context) error
 	Reboot(context.Context) error
+	Modify(context.Context) error
+	Start(context.Context) error
+	Stop(context.Context) error
+	CreateReadReplica(context.Context) error
+	PromoteReadReplica(context.Context) error
+	DescribeReplicaTree(context.Context) (*ReplicaNode, error)
 	Describe(context.Context) (*DescInstance, error)
 	DescribeAll(ctx context.Context) ([]*DescInstance, error)
+	List(context.Context) ([]*DescInstance, error)
+	Iterate(ctx context.Context, fn func(*DescInstance) bool) error
+	ListSnapshots(context.Context) ([]*DescSnapshot, error)
+	IterateSnapshots(ctx context.Context, fn func(*DescSnapshot) bool) error
 	RestorePitr(context.Context) error
 	CreateSnapshot(context.Context) error
 	DescribeSnapshot(context.Context) (*DescSnapshot, error)
+	DeleteSnapshot(context.Context) error
+	CopySnapshot(context.Context) error
+	WaitForSnapshot(ctx context.Context, opts *WaitOptions) error
+	ShareSnapshot(ctx context.Context, accounts ...string) error
+	UnshareSnapshot(ctx context.Context, accounts ...string) error
 	RestoreFromSnapshot(context.Context) error
 	RestoreToPitr(context.Context) error
+	WaitFor(ctx context.Context, status DBInstanceStatus, opts *WaitOptions) error
+	Watch(ctx context.Context) <-chan StatusEvent
+	Events(ctx context.Context, sourceID string, since time.Time) ([]*Event, error)
+	ListLogFiles(context.Context) ([]LogFile, error)
+	StreamLogFile(ctx context.Context, file LogFile, opts *LogStreamOptions) (io.ReadCloser, error)
 }
 
 type rdsInstance struct {
@@ -77,6 +115,38 @@ type rdsInstance struct {
 	createSnapshotParam      *rds.CreateDBSnapshotInput
 	describeSnapshotParam    *rds.DescribeDBSnapshotsInput
 	restoreFromSnapshotParam *rds.RestoreDBInstanceFromDBSnapshotInput
+	modifyInstanceParam      *rds.ModifyDBInstanceInput
+	startInstanceParam       *rds.StartDBInstanceInput
+	stopInstanceParam        *rds.StopDBInstanceInput
+	createReadReplicaParam   *rds.CreateDBInstanceReadReplicaInput
+	promoteReadReplicaParam  *rds.PromoteReadReplicaInput
+	deleteSnapshotParam      *rds.DeleteDBSnapshotInput
+	copySnapshotParam        *rds.CopyDBSnapshotInput
+
+	maxResults    int32
+	tagFilters    []types.Filter
+	watchInterval time.Duration
+}
+
+func newInstance(core *rds.Client) *rdsInstance {
+	return &rdsInstance{
+		core:                     core,
+		createInstanceParam:      &rds.CreateDBInstanceInput{},
+		deleteInstanceParam:      &rds.DeleteDBInstanceInput{},
+		rebootInstanceParam:      &rds.RebootDBInstanceInput{},
+		describeInstanceParam:    &rds.DescribeDBInstancesInput{},
+		restoreInstancePitrParam: &rds.RestoreDBInstanceToPointInTimeInput{},
+		createSnapshotParam:      &rds.CreateDBSnapshotInput{},
+		describeSnapshotParam:    &rds.DescribeDBSnapshotsInput{},
+		restoreFromSnapshotParam: &rds.RestoreDBInstanceFromDBSnapshotInput{},
+		modifyInstanceParam:      &rds.ModifyDBInstanceInput{},
+		startInstanceParam:       &rds.StartDBInstanceInput{},
+		stopInstanceParam:        &rds.StopDBInstanceInput{},
+		createReadReplicaParam:   &rds.CreateDBInstanceReadReplicaInput{},
+		promoteReadReplicaParam:  &rds.PromoteReadReplicaInput{},
+		deleteSnapshotParam:      &rds.DeleteDBSnapshotInput{},
+		copySnapshotParam:        &rds.CopyDBSnapshotInput{},
+	}
 }
 
 type ReadReplicaStatus struct {
@@ -99,14 +169,49 @@ type DescInstance struct {
 	DeletionProtection                    bool
 	InstanceCreateTime                    time.Time
 	Timezone                              string
+	AvailabilityZone                      string
 	SecondaryAZ                           string
 	ReadReplicaSourceDBInstanceIdentifier string
 	ReadReplicaDBInstanceIdentifiers      []string
 	ReadReplicaStatusInfos                []ReadReplicaStatus
 	Endpoint                              Endpoint
 	DBParameterGroups                     []ParameterGroupStatus
+	OptionGroups                          []OptionGroupStatus
+	DBSubnetGroupName                     string
 	DBClusterIdentifier                   string
 	ReadReplicaDBClusterIdentifiers       []string
+	Engine                                string
+	EngineVersion                         string
+	DBInstanceClass                       string
+	AllocatedStorage                      int32
+	MaxAllocatedStorage                   int32
+	Iops                                  int32
+	StorageType                           string
+	BackupRetentionPeriod                 int32
+	PreferredMaintenanceWindow            string
+	PendingModifiedValues                 *PendingModifiedValues
+	Tags                                  map[string]string
+}
+
+// PendingModifiedValues are the changes of a modification which are not
+// applied yet. Zero values are not pending.
+type PendingModifiedValues struct {
+	DBInstanceClass            string
+	AllocatedStorage           int32
+	Iops                       int32
+	StorageType                string
+	BackupRetentionPeriod      int32
+	EngineVersion              string
+	MultiAZ                    *bool
+	MasterUserPasswordPending  bool
+	DBInstanceIdentifier       string
+	DBSubnetGroupName          string
+	LicenseModel               string
+	Port                       int32
+	CACertificateIdentifier    string
+	StorageThroughput          int32
+	IAMDatabaseAuthentication  *bool
+	ResumeFullAutomationModeAt time.Time
 }
 
 type DescSnapshot struct {
@@ -121,6 +226,7 @@ type DescSnapshot struct {
 	SnapshotDatabaseTime time.Time
 	SnapshotType         string
 	Status               string
+	Tags                 map[string]string
 }
 
 type DBInstanceStatus string
@@ -138,6 +244,18 @@ const (
 	DBInstanceStatusStopped   DBInstanceStatus = "stopped"
 	DBInstanceStatusStopping  DBInstanceStatus = "stopping"
 	DBInstanceStatusReady     DBInstanceStatus = "Ready"
+
+	DBInstanceStatusRestoreError                      DBInstanceStatus = "restore-error"
+	DBInstanceStatusInaccessibleEncryptionCredentials DBInstanceStatus = "inaccessible-encryption-credentials"
+	DBInstanceStatusIncompatibleCredentials           DBInstanceStatus = "incompatible-credentials"
+	DBInstanceStatusIncompatibleNetwork               DBInstanceStatus = "incompatible-network"
+	DBInstanceStatusIncompatibleOptionGroup           DBInstanceStatus = "incompatible-option-group"
+	DBInstanceStatusIncompatibleParameters            DBInstanceStatus = "incompatible-parameters"
+	DBInstanceStatusIncompatibleRestore               DBInstanceStatus = "incompatible-restore"
+
+	// DBInstanceStatusDeleted is not reported by AWS, WaitFor uses it to wait
+	// until the instance is not found anymore.
+	DBInstanceStatusDeleted DBInstanceStatus = "deleted"
 )
 
 type ParameterGroupStatus struct {
@@ -160,6 +278,11 @@ func (s *rdsInstance) SetEngineVersion(version string) Instance {
 
 func (s *rdsInstance) SetDBInstanceIdentifier(id string) Instance {
 	s.createInstanceParam.DBInstanceIdentifier = aws.String(id)
+	s.modifyInstanceParam.DBInstanceIdentifier = aws.String(id)
+	s.startInstanceParam.DBInstanceIdentifier = aws.String(id)
+	s.stopInstanceParam.DBInstanceIdentifier = aws.String(id)
+	s.createReadReplicaParam.DBInstanceIdentifier = aws.String(id)
+	s.promoteReadReplicaParam.DBInstanceIdentifier = aws.String(id)
 	s.deleteInstanceParam.DBInstanceIdentifier = aws.String(id)
 	s.rebootInstanceParam.DBInstanceIdentifier = aws.String(id)
 	s.describeInstanceParam.DBInstanceIdentifier = aws.String(id)
@@ -176,6 +299,7 @@ func (s *rdsInstance) SetMasterUsername(username string) Instance {
 
 func (s *rdsInstance) SetMasterUserPassword(pass string) Instance {
 	s.createInstanceParam.MasterUserPassword = aws.String(pass)
+	s.modifyInstanceParam.MasterUserPassword = aws.String(pass)
 	return s
 }
 
@@ -183,11 +307,14 @@ func (s *rdsInstance) SetDBInstanceClass(class string) Instance {
 	s.createInstanceParam.DBInstanceClass = aws.String(class)
 	s.restoreInstancePitrParam.DBInstanceClass = aws.String(class)
 	s.restoreFromSnapshotParam.DBInstanceClass = aws.String(class)
+	s.modifyInstanceParam.DBInstanceClass = aws.String(class)
+	s.createReadReplicaParam.DBInstanceClass = aws.String(class)
 	return s
 }
 
 func (s *rdsInstance) SetAllocatedStorage(size int32) Instance {
 	s.createInstanceParam.AllocatedStorage = aws.Int32(size)
+	s.modifyInstanceParam.AllocatedStorage = aws.Int32(size)
 	// s.restoreInstancePitrParam.MaxAllocatedStorage = aws.Int32(size)
 	return s
 }
@@ -196,6 +323,73 @@ func (s *rdsInstance) SetIOPS(iops int32) Instance {
 	s.createInstanceParam.Iops = aws.Int32(iops)
 	s.restoreInstancePitrParam.Iops = aws.Int32(iops)
 	s.restoreFromSnapshotParam.Iops = aws.Int32(iops)
+	s.modifyInstanceParam.Iops = aws.Int32(iops)
+	s.createReadReplicaParam.Iops = aws.Int32(iops)
+	return s
+}
+
+func (s *rdsInstance) SetStorageType(t string) Instance {
+	s.createInstanceParam.StorageType = aws.String(t)
+	s.restoreInstancePitrParam.StorageType = aws.String(t)
+	s.restoreFromSnapshotParam.StorageType = aws.String(t)
+	s.modifyInstanceParam.StorageType = aws.String(t)
+	s.createReadReplicaParam.StorageType = aws.String(t)
+	return s
+}
+
+// SetMaxAllocatedStorage sets the upper limit in GiB of storage autoscaling.
+func (s *rdsInstance) SetMaxAllocatedStorage(size int32) Instance {
+	s.createInstanceParam.MaxAllocatedStorage = aws.Int32(size)
+	s.restoreInstancePitrParam.MaxAllocatedStorage = aws.Int32(size)
+	s.modifyInstanceParam.MaxAllocatedStorage = aws.Int32(size)
+	s.createReadReplicaParam.MaxAllocatedStorage = aws.Int32(size)
+	return s
+}
+
+func (s *rdsInstance) SetBackupRetentionPeriod(days int32) Instance {
+	s.createInstanceParam.BackupRetentionPeriod = aws.Int32(days)
+	s.modifyInstanceParam.BackupRetentionPeriod = aws.Int32(days)
+	s.promoteReadReplicaParam.BackupRetentionPeriod = aws.Int32(days)
+	return s
+}
+
+// SetPreferredMaintenanceWindow sets the weekly maintenance window in UTC, formatted ddd:hh24:mi-ddd:hh24:mi.
+func (s *rdsInstance) SetPreferredMaintenanceWindow(window string) Instance {
+	s.createInstanceParam.PreferredMaintenanceWindow = aws.String(window)
+	s.modifyInstanceParam.PreferredMaintenanceWindow = aws.String(window)
+	return s
+}
+
+func (s *rdsInstance) SetDeletionProtection(enable bool) Instance {
+	s.createInstanceParam.DeletionProtection = aws.Bool(enable)
+	s.restoreInstancePitrParam.DeletionProtection = aws.Bool(enable)
+	s.restoreFromSnapshotParam.DeletionProtection = aws.Bool(enable)
+	s.modifyInstanceParam.DeletionProtection = aws.Bool(enable)
+	s.createReadReplicaParam.DeletionProtection = aws.Bool(enable)
+	return s
+}
+
+func (s *rdsInstance) SetDBParameterGroupName(name string) Instance {
+	s.createInstanceParam.DBParameterGroupName = aws.String(name)
+	s.restoreInstancePitrParam.DBParameterGroupName = aws.String(name)
+	s.restoreFromSnapshotParam.DBParameterGroupName = aws.String(name)
+	s.modifyInstanceParam.DBParameterGroupName = aws.String(name)
+	s.createReadReplicaParam.DBParameterGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsInstance) SetOptionGroupName(name string) Instance {
+	s.createInstanceParam.OptionGroupName = aws.String(name)
+	s.restoreInstancePitrParam.OptionGroupName = aws.String(name)
+	s.restoreFromSnapshotParam.OptionGroupName = aws.String(name)
+	s.modifyInstanceParam.OptionGroupName = aws.String(name)
+	s.createReadReplicaParam.OptionGroupName = aws.String(name)
+	return s
+}
+
+// SetApplyImmediately applies Modify now instead of during the next maintenance window.
+func (s *rdsInstance) SetApplyImmediately(enable bool) Instance {
+	s.modifyInstanceParam.ApplyImmediately = enable
 	return s
 }
 
@@ -211,6 +405,7 @@ func (s *rdsInstance) SetVpcSecurityGroupIds(sgs []string) Instance {
 	s.createInstanceParam.VpcSecurityGroupIds = sgs
 	s.restoreInstancePitrParam.VpcSecurityGroupIds = sgs
 	s.restoreFromSnapshotParam.VpcSecurityGroupIds = sgs
+	s.createReadReplicaParam.VpcSecurityGroupIds = sgs
 	return s
 }
 
@@ -218,6 +413,7 @@ func (s *rdsInstance) SetDBSubnetGroup(name string) Instance {
 	s.createInstanceParam.DBSubnetGroupName = aws.String(name)
 	s.restoreInstancePitrParam.DBSubnetGroupName = aws.String(name)
 	s.restoreFromSnapshotParam.DBSubnetGroupName = aws.String(name)
+	s.createReadReplicaParam.DBSubnetGroupName = aws.String(name)
 	return s
 }
 
@@ -225,6 +421,7 @@ func (s *rdsInstance) SetMultiAZ(enable bool) Instance {
 	s.createInstanceParam.MultiAZ = aws.Bool(enable)
 	s.restoreInstancePitrParam.MultiAZ = aws.Bool(enable)
 	s.restoreFromSnapshotParam.MultiAZ = aws.Bool(enable)
+	s.createReadReplicaParam.MultiAZ = aws.Bool(enable)
 	return s
 }
 
@@ -232,6 +429,7 @@ func (s *rdsInstance) SetAvailabilityZones(az string) Instance {
 	s.createInstanceParam.AvailabilityZone = aws.String(az)
 	s.restoreInstancePitrParam.AvailabilityZone = aws.String(az)
 	s.restoreFromSnapshotParam.AvailabilityZone = aws.String(az)
+	s.createReadReplicaParam.AvailabilityZone = aws.String(az)
 	return s
 }
 
@@ -285,6 +483,7 @@ func (s *rdsInstance) SetSourceDBInstanceAutomatedBackupsArn(arn string) Instanc
 
 func (s *rdsInstance) SetSourceDBInstanceIdentifier(sid string) Instance {
 	s.restoreInstancePitrParam.SourceDBInstanceIdentifier = aws.String(sid)
+	s.createReadReplicaParam.SourceDBInstanceIdentifier = aws.String(sid)
 	return s
 }
 
@@ -307,6 +506,7 @@ func (s *rdsInstance) SetPublicAccessible(enable bool) Instance {
 	s.createInstanceParam.PubliclyAccessible = aws.Bool(enable)
 	s.restoreInstancePitrParam.PubliclyAccessible = aws.Bool(enable)
 	s.restoreFromSnapshotParam.PubliclyAccessible = aws.Bool(enable)
+	s.createReadReplicaParam.PubliclyAccessible = aws.Bool(enable)
 	return s
 }
 
@@ -319,45 +519,33 @@ func (s *rdsInstance) SetLicenseModel(model string) Instance {
 
 func (s *rdsInstance) RestorePitr(ctx context.Context) error {
 	_, err := s.core.RestoreDBInstanceToPointInTime(ctx, s.restoreInstancePitrParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsInstance) SetSnapshotIdentifier(id string) Instance {
 	s.createSnapshotParam.DBSnapshotIdentifier = aws.String(id)
 	s.describeSnapshotParam.DBSnapshotIdentifier = aws.String(id)
 	s.restoreFromSnapshotParam.DBSnapshotIdentifier = aws.String(id)
+	s.deleteSnapshotParam.DBSnapshotIdentifier = aws.String(id)
+	s.copySnapshotParam.SourceDBSnapshotIdentifier = aws.String(id)
 	return s
 }
 
+// SetFilter sets a filter of List, such as engine or, with TagFilterPrefix, a tag.
 func (s *rdsInstance) SetFilter(name string, values []string) Instance {
-	var exists = false
-	for _, filter := range s.describeInstanceParam.Filters {
-		if filter.Name == nil {
-			continue
-		}
-		if *filter.Name == name {
-			filter.Values = values
-			exists = true
-		}
-	}
-	if !exists {
-		s.describeInstanceParam.Filters = append(s.describeInstanceParam.Filters, types.Filter{
-			Name:   aws.String(name),
-			Values: values,
-		})
-	}
+	s.describeInstanceParam.Filters, s.tagFilters = setDescribeFilter(s.describeInstanceParam.Filters, s.tagFilters, name, values)
 	return s
 }
 
 func (s *rdsInstance) Create(ctx context.Context) error {
 	_, err := s.core.CreateDBInstance(ctx, s.createInstanceParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsInstance) Delete(ctx context.Context) error {
 	_, err := s.core.DeleteDBInstance(ctx, s.deleteInstanceParam)
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBInstanceNotFoundFault); ok {
+	if err = wrapError(err); err != nil {
+		if errors.Is(err, ErrNotFound) {
 			return nil
 		}
 		return err
@@ -365,20 +553,44 @@ func (s *rdsInstance) Delete(ctx context.Context) error {
 	return nil
 }
 
+// SetStopSnapshotIdentifier makes Stop take a snapshot named id before stopping.
+func (s *rdsInstance) SetStopSnapshotIdentifier(id string) Instance {
+	s.stopInstanceParam.DBSnapshotIdentifier = aws.String(id)
+	return s
+}
+
+func (s *rdsInstance) Start(ctx context.Context) error {
+	_, err := s.core.StartDBInstance(ctx, s.startInstanceParam)
+	return wrapError(err)
+}
+
+// Stop stops the instance, which rds starts again by itself after seven days.
+func (s *rdsInstance) Stop(ctx context.Context) error {
+	_, err := s.core.StopDBInstance(ctx, s.stopInstanceParam)
+	return wrapError(err)
+}
+
 // Reboot
 // NOTE: Can only reboot db instances with state in: available, storage-optimization, incompatible-credentials, incompatible-parameters.
 func (s *rdsInstance) Reboot(ctx context.Context) error {
 	_, err := s.core.RebootDBInstance(ctx, s.rebootInstanceParam)
-	return err
+	return wrapError(err)
+}
+
+// Modify changes the instance with the values set by SetDBInstanceClass,
+// SetAllocatedStorage, SetMaxAllocatedStorage, SetIOPS, SetStorageType,
+// SetBackupRetentionPeriod, SetPreferredMaintenanceWindow, SetDeletionProtection,
+// SetDBParameterGroupName and SetMasterUserPassword. The changes are pending
+// until the next maintenance window unless SetApplyImmediately is set.
+func (s *rdsInstance) Modify(ctx context.Context) error {
+	_, err := s.core.ModifyDBInstance(ctx, s.modifyInstanceParam)
+	return wrapError(err)
 }
 
 func (s *rdsInstance) CreateSnapshot(ctx context.Context) error {
 	snapshot, err := s.DescribeSnapshot(ctx)
-
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBClusterSnapshotNotFoundFault); !ok {
-			return err
-		}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
 	}
 
 	if snapshot != nil {
@@ -386,13 +598,13 @@ func (s *rdsInstance) CreateSnapshot(ctx context.Context) error {
 	}
 
 	_, err = s.core.CreateDBSnapshot(ctx, s.createSnapshotParam)
-	return err
+	return wrapError(err)
 }
 
 func (s *rdsInstance) DescribeSnapshot(ctx context.Context) (*DescSnapshot, error) {
 	resp, err := s.core.DescribeDBSnapshots(ctx, s.describeSnapshotParam)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 	if len(resp.DBSnapshots) == 0 {
 		return nil, nil
@@ -402,8 +614,8 @@ func (s *rdsInstance) DescribeSnapshot(ctx context.Context) (*DescSnapshot, erro
 
 func (s *rdsInstance) Describe(ctx context.Context) (*DescInstance, error) {
 	output, err := s.core.DescribeDBInstances(ctx, s.describeInstanceParam)
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBInstanceNotFoundFault); ok {
+	if err = wrapError(err); err != nil {
+		if errors.Is(err, ErrNotFound) {
 			return nil, nil
 		}
 		return nil, err
@@ -416,25 +628,15 @@ func (s *rdsInstance) Describe(ctx context.Context) (*DescInstance, error) {
 	return desc, nil
 }
 
+// DescribeAll is List.
 func (s *rdsInstance) DescribeAll(ctx context.Context) ([]*DescInstance, error) {
-	output, err := s.core.DescribeDBInstances(ctx, s.describeInstanceParam)
-	if err != nil {
-		if _, ok := errors.Unwrap(err.(*smithy.OperationError).Err).(*types.DBInstanceNotFoundFault); ok {
-			return nil, nil
-		}
-		return nil, err
-	}
-	var descs []*DescInstance
-	for _, dbInstance := range output.DBInstances {
-		descs = append(descs, convertDBInstance(&dbInstance))
-	}
-	return descs, nil
+	return s.List(ctx)
 }
 
 func (s *rdsInstance) RestoreFromSnapshot(ctx context.Context) error {
 	_, err := s.core.RestoreDBInstanceFromDBSnapshot(ctx, s.restoreFromSnapshotParam)
 	if err != nil {
-		return err
+		return wrapError(err)
 	}
 
 	return nil
@@ -443,12 +645,26 @@ func (s *rdsInstance) RestoreFromSnapshot(ctx context.Context) error {
 func (s *rdsInstance) RestoreToPitr(ctx context.Context) error {
 	_, err := s.core.RestoreDBInstanceToPointInTime(ctx, s.restoreInstancePitrParam)
 	if err != nil {
-		return err
+		return wrapError(err)
 	}
 
 	return nil
 }
 
+// setFilter replaces the values of the filter name, or appends it.
+func setFilter(filters []types.Filter, name string, values []string) []types.Filter {
+	for i := range filters {
+		if aws.ToString(filters[i].Name) == name {
+			filters[i].Values = values
+			return filters
+		}
+	}
+	return append(filters, types.Filter{
+		Name:   aws.String(name),
+		Values: values,
+	})
+}
+
 func convertDBSnapshot(in *types.DBSnapshot) *DescSnapshot {
 	return &DescSnapshot{
 		DBInstanceIdentifier: aws.ToString(in.DBInstanceIdentifier),
@@ -462,6 +678,7 @@ func convertDBSnapshot(in *types.DBSnapshot) *DescSnapshot {
 		SnapshotDatabaseTime: aws.ToTime(in.SnapshotDatabaseTime),
 		SnapshotType:         aws.ToString(in.SnapshotType),
 		Status:               aws.ToString(in.Status),
+		Tags:                 convertTags(in.TagList),
 	}
 }
 
@@ -477,6 +694,7 @@ func convertDBInstance(dbInstance *types.DBInstance) *DescInstance {
 	desc.DeletionProtection = dbInstance.DeletionProtection
 	desc.InstanceCreateTime = aws.ToTime(dbInstance.InstanceCreateTime)
 	desc.Timezone = aws.ToString(dbInstance.Timezone)
+	desc.AvailabilityZone = aws.ToString(dbInstance.AvailabilityZone)
 	desc.SecondaryAZ = aws.ToString(dbInstance.SecondaryAvailabilityZone)
 	desc.ReadReplicaSourceDBInstanceIdentifier = aws.ToString(dbInstance.ReadReplicaSourceDBInstanceIdentifier)
 	desc.ReadReplicaDBInstanceIdentifiers = dbInstance.ReadReplicaDBInstanceIdentifiers
@@ -485,11 +703,50 @@ func convertDBInstance(dbInstance *types.DBInstance) *DescInstance {
 		desc.Endpoint = convertEndpoint(dbInstance.Endpoint)
 	}
 	desc.DBParameterGroups = convertParameterGroupStatus(dbInstance.DBParameterGroups)
+	desc.OptionGroups = convertOptionGroupStatus(dbInstance.OptionGroupMemberships)
+	if dbInstance.DBSubnetGroup != nil {
+		desc.DBSubnetGroupName = aws.ToString(dbInstance.DBSubnetGroup.DBSubnetGroupName)
+	}
 	desc.DBClusterIdentifier = aws.ToString(dbInstance.DBClusterIdentifier)
 	desc.ReadReplicaDBClusterIdentifiers = dbInstance.ReadReplicaDBClusterIdentifiers
+	desc.Engine = aws.ToString(dbInstance.Engine)
+	desc.EngineVersion = aws.ToString(dbInstance.EngineVersion)
+	desc.DBInstanceClass = aws.ToString(dbInstance.DBInstanceClass)
+	desc.AllocatedStorage = dbInstance.AllocatedStorage
+	desc.MaxAllocatedStorage = aws.ToInt32(dbInstance.MaxAllocatedStorage)
+	desc.Iops = aws.ToInt32(dbInstance.Iops)
+	desc.StorageType = aws.ToString(dbInstance.StorageType)
+	desc.BackupRetentionPeriod = dbInstance.BackupRetentionPeriod
+	desc.PreferredMaintenanceWindow = aws.ToString(dbInstance.PreferredMaintenanceWindow)
+	desc.PendingModifiedValues = convertPendingModifiedValues(dbInstance.PendingModifiedValues)
+	desc.Tags = convertTags(dbInstance.TagList)
 	return desc
 }
 
+func convertPendingModifiedValues(in *types.PendingModifiedValues) *PendingModifiedValues {
+	if in == nil {
+		return nil
+	}
+	return &PendingModifiedValues{
+		DBInstanceClass:            aws.ToString(in.DBInstanceClass),
+		AllocatedStorage:           aws.ToInt32(in.AllocatedStorage),
+		Iops:                       aws.ToInt32(in.Iops),
+		StorageType:                aws.ToString(in.StorageType),
+		BackupRetentionPeriod:      aws.ToInt32(in.BackupRetentionPeriod),
+		EngineVersion:              aws.ToString(in.EngineVersion),
+		MultiAZ:                    in.MultiAZ,
+		MasterUserPasswordPending:  in.MasterUserPassword != nil,
+		DBInstanceIdentifier:       aws.ToString(in.DBInstanceIdentifier),
+		DBSubnetGroupName:          aws.ToString(in.DBSubnetGroupName),
+		LicenseModel:               aws.ToString(in.LicenseModel),
+		Port:                       aws.ToInt32(in.Port),
+		CACertificateIdentifier:    aws.ToString(in.CACertificateIdentifier),
+		StorageThroughput:          aws.ToInt32(in.StorageThroughput),
+		IAMDatabaseAuthentication:  in.IAMDatabaseAuthenticationEnabled,
+		ResumeFullAutomationModeAt: aws.ToTime(in.ResumeFullAutomationModeTime),
+	}
+}
+
 func convertReadReplicaStatus(infos []types.DBInstanceStatusInfo) []ReadReplicaStatus {
 	var readReplicaStatusInfos []ReadReplicaStatus
 	for _, info := range infos {
@@ -0,0 +1,270 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	minPageSize = 20
+	maxPageSize = 100
+)
+
+// paginate calls fn with every item of every page until the last page, until
+// fn returns false or until max items were seen when max is positive.
+func paginate[T any](ctx context.Context, max int32, next func(ctx context.Context, marker *string, pageSize int32) ([]T, *string, error), fn func(T) bool) error {
+	pageSize := int32(maxPageSize)
+	if max > 0 && max < pageSize {
+		pageSize = max
+		if pageSize < minPageSize {
+			pageSize = minPageSize
+		}
+	}
+
+	var (
+		marker *string
+		seen   int32
+	)
+	for {
+		items, nextMarker, err := next(ctx, marker, pageSize)
+		if err != nil {
+			return wrapError(err)
+		}
+		for _, item := range items {
+			if !fn(item) {
+				return nil
+			}
+			seen++
+			if max > 0 && seen >= max {
+				return nil
+			}
+		}
+		if aws.ToString(nextMarker) == "" {
+			return nil
+		}
+		marker = nextMarker
+	}
+}
+
+// ignoreNotFound drops the not found error of a listing whose missing
+// resource means an empty result, such as instances listed by identifier.
+func ignoreNotFound(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// collect returns the items passed to iterate.
+func collect[T any](iterate func(fn func(T) bool) error) ([]T, error) {
+	var items []T
+	err := iterate(func(item T) bool {
+		items = append(items, item)
+		return true
+	})
+	return items, err
+}
+
+func iterateInstances(ctx context.Context, core *rds.Client, in *rds.DescribeDBInstancesInput, tags []types.Filter, max int32, fn func(*DescInstance) bool) error {
+	return ignoreNotFound(paginate(ctx, max, func(ctx context.Context, marker *string, pageSize int32) ([]*DescInstance, *string, error) {
+		param := *in
+		param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+		out, err := core.DescribeDBInstances(ctx, &param)
+		if err != nil {
+			return nil, nil, err
+		}
+		var descs []*DescInstance
+		for i := range out.DBInstances {
+			if desc := convertDBInstance(&out.DBInstances[i]); matchTagFilters(desc.Tags, tags) {
+				descs = append(descs, desc)
+			}
+		}
+		return descs, out.Marker, nil
+	}, fn))
+}
+
+func iterateClusters(ctx context.Context, core *rds.Client, in *rds.DescribeDBClustersInput, tags []types.Filter, max int32, fn func(*DescCluster) bool) error {
+	return ignoreNotFound(paginate(ctx, max, func(ctx context.Context, marker *string, pageSize int32) ([]*DescCluster, *string, error) {
+		param := *in
+		param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+		out, err := core.DescribeDBClusters(ctx, &param)
+		if err != nil {
+			return nil, nil, err
+		}
+		var descs []*DescCluster
+		for i := range out.DBClusters {
+			if desc := convertDBCluster(&out.DBClusters[i]); matchTagFilters(desc.Tags, tags) {
+				descs = append(descs, desc)
+			}
+		}
+		return descs, out.Marker, nil
+	}, fn))
+}
+
+func iterateSnapshots(ctx context.Context, core *rds.Client, in *rds.DescribeDBSnapshotsInput, max int32, fn func(*DescSnapshot) bool) error {
+	return ignoreNotFound(paginate(ctx, max, func(ctx context.Context, marker *string, pageSize int32) ([]*DescSnapshot, *string, error) {
+		param := *in
+		param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+		out, err := core.DescribeDBSnapshots(ctx, &param)
+		if err != nil {
+			return nil, nil, err
+		}
+		var descs []*DescSnapshot
+		for i := range out.DBSnapshots {
+			descs = append(descs, convertDBSnapshot(&out.DBSnapshots[i]))
+		}
+		return descs, out.Marker, nil
+	}, fn))
+}
+
+func iterateClusterSnapshots(ctx context.Context, core *rds.Client, in *rds.DescribeDBClusterSnapshotsInput, max int32, fn func(*DescClusterSnapshot) bool) error {
+	return ignoreNotFound(paginate(ctx, max, func(ctx context.Context, marker *string, pageSize int32) ([]*DescClusterSnapshot, *string, error) {
+		param := *in
+		param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+		out, err := core.DescribeDBClusterSnapshots(ctx, &param)
+		if err != nil {
+			return nil, nil, err
+		}
+		var descs []*DescClusterSnapshot
+		for i := range out.DBClusterSnapshots {
+			descs = append(descs, convertDBClusterSnapshot(&out.DBClusterSnapshots[i]))
+		}
+		return descs, out.Marker, nil
+	}, fn))
+}
+
+// SetMaxResults caps the number of items returned by List and ListSnapshots, 0 for no cap.
+func (s *rdsInstance) SetMaxResults(max int32) Instance {
+	s.maxResults = max
+	return s
+}
+
+// SetSnapshotFilter sets a filter of ListSnapshots, such as db-instance-id or snapshot-type.
+func (s *rdsInstance) SetSnapshotFilter(name string, values []string) Instance {
+	s.describeSnapshotParam.Filters = setFilter(s.describeSnapshotParam.Filters, name, values)
+	return s
+}
+
+// List returns every instance matching the identifier and the filters.
+func (s *rdsInstance) List(ctx context.Context) ([]*DescInstance, error) {
+	return collect(func(fn func(*DescInstance) bool) error { return s.Iterate(ctx, fn) })
+}
+
+// Iterate calls fn with every instance matching the identifier and the
+// filters, one page at a time, until fn returns false.
+func (s *rdsInstance) Iterate(ctx context.Context, fn func(*DescInstance) bool) error {
+	return iterateInstances(ctx, s.core, s.describeInstanceParam, s.tagFilters, s.maxResults, fn)
+}
+
+// ListSnapshots returns every snapshot matching the snapshot identifier and the snapshot filters.
+func (s *rdsInstance) ListSnapshots(ctx context.Context) ([]*DescSnapshot, error) {
+	return collect(func(fn func(*DescSnapshot) bool) error { return s.IterateSnapshots(ctx, fn) })
+}
+
+func (s *rdsInstance) IterateSnapshots(ctx context.Context, fn func(*DescSnapshot) bool) error {
+	return iterateSnapshots(ctx, s.core, s.describeSnapshotParam, s.maxResults, fn)
+}
+
+// SetFilter sets a filter of List, such as engine or, with TagFilterPrefix, a tag.
+func (s *rdsCluster) SetFilter(name string, values []string) Cluster {
+	s.describeClusterParam.Filters, s.tagFilters = setDescribeFilter(s.describeClusterParam.Filters, s.tagFilters, name, values)
+	return s
+}
+
+// SetMaxResults caps the number of items returned by List and ListSnapshots, 0 for no cap.
+func (s *rdsCluster) SetMaxResults(max int32) Cluster {
+	s.maxResults = max
+	return s
+}
+
+// SetSnapshotFilter sets a filter of ListSnapshots, such as db-cluster-id or snapshot-type.
+func (s *rdsCluster) SetSnapshotFilter(name string, values []string) Cluster {
+	s.describeDBClusterSnapshotParam.Filters = setFilter(s.describeDBClusterSnapshotParam.Filters, name, values)
+	return s
+}
+
+// List returns every cluster matching the identifier and the filters.
+func (s *rdsCluster) List(ctx context.Context) ([]*DescCluster, error) {
+	return collect(func(fn func(*DescCluster) bool) error { return s.Iterate(ctx, fn) })
+}
+
+// Iterate calls fn with every cluster matching the identifier and the
+// filters, one page at a time, until fn returns false.
+func (s *rdsCluster) Iterate(ctx context.Context, fn func(*DescCluster) bool) error {
+	return iterateClusters(ctx, s.core, s.describeClusterParam, s.tagFilters, s.maxResults, fn)
+}
+
+// ListSnapshots returns every snapshot matching the snapshot identifier and the snapshot filters.
+func (s *rdsCluster) ListSnapshots(ctx context.Context) ([]*DescClusterSnapshot, error) {
+	return collect(func(fn func(*DescClusterSnapshot) bool) error { return s.IterateSnapshots(ctx, fn) })
+}
+
+func (s *rdsCluster) IterateSnapshots(ctx context.Context, fn func(*DescClusterSnapshot) bool) error {
+	return iterateClusterSnapshots(ctx, s.core, s.describeDBClusterSnapshotParam, s.maxResults, fn)
+}
+
+// SetFilter sets a filter of List, such as engine or, with TagFilterPrefix, a tag.
+func (s *rdsAurora) SetFilter(name string, values []string) Aurora {
+	s.describeClusterParam.Filters, s.tagFilters = setDescribeFilter(s.describeClusterParam.Filters, s.tagFilters, name, values)
+	return s
+}
+
+// SetMaxResults caps the number of items returned by List and ListSnapshots, 0 for no cap.
+func (s *rdsAurora) SetMaxResults(max int32) Aurora {
+	s.maxResults = max
+	return s
+}
+
+// SetSnapshotFilter sets a filter of ListSnapshots, such as db-cluster-id or snapshot-type.
+func (s *rdsAurora) SetSnapshotFilter(name string, values []string) Aurora {
+	s.describeClusterSnapshotParam.Filters = setFilter(s.describeClusterSnapshotParam.Filters, name, values)
+	return s
+}
+
+// List returns every cluster matching the identifier and the filters.
+func (s *rdsAurora) List(ctx context.Context) ([]*DescCluster, error) {
+	return collect(func(fn func(*DescCluster) bool) error { return s.Iterate(ctx, fn) })
+}
+
+// Iterate calls fn with every cluster matching the identifier and the
+// filters, one page at a time, until fn returns false.
+func (s *rdsAurora) Iterate(ctx context.Context, fn func(*DescCluster) bool) error {
+	var endpointsErr error
+	err := iterateClusters(ctx, s.core, s.describeClusterParam, s.tagFilters, s.maxResults, func(desc *DescCluster) bool {
+		if endpointsErr = describeEndpoints(ctx, s.core, desc); endpointsErr != nil {
+			return false
+		}
+		return fn(desc)
+	})
+	if err != nil {
+		return err
+	}
+	return endpointsErr
+}
+
+// ListSnapshots returns every snapshot matching the snapshot identifier and the snapshot filters.
+func (s *rdsAurora) ListSnapshots(ctx context.Context) ([]*DescClusterSnapshot, error) {
+	return collect(func(fn func(*DescClusterSnapshot) bool) error { return s.IterateSnapshots(ctx, fn) })
+}
+
+func (s *rdsAurora) IterateSnapshots(ctx context.Context, fn func(*DescClusterSnapshot) bool) error {
+	return iterateClusterSnapshots(ctx, s.core, s.describeClusterSnapshotParam, s.maxResults, fn)
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// pages serves total items as pages, recording the requested page sizes.
+func pages(total int, sizes *[]int32) func(context.Context, *string, int32) ([]int, *string, error) {
+	return func(_ context.Context, marker *string, pageSize int32) ([]int, *string, error) {
+		*sizes = append(*sizes, pageSize)
+		start, _ := strconv.Atoi(aws.ToString(marker))
+		var items []int
+		for i := start; i < total && i < start+int(pageSize); i++ {
+			items = append(items, i)
+		}
+		if start+int(pageSize) >= total {
+			return items, nil, nil
+		}
+		return items, aws.String(strconv.Itoa(start + int(pageSize))), nil
+	}
+}
+
+var _ = Describe("List", func() {
+	It("should follow the markers until the last page", func() {
+		var sizes []int32
+		items, err := collect(func(fn func(int) bool) error {
+			return paginate(context.Background(), 0, pages(250, &sizes), fn)
+		})
+		Expect(err).To(BeNil())
+		Expect(items).To(HaveLen(250))
+		Expect(items[249]).To(Equal(249))
+		Expect(sizes).To(Equal([]int32{100, 100, 100}))
+	})
+
+	It("should stop at max results and when the iterator returns false", func() {
+		var sizes []int32
+		items, err := collect(func(fn func(int) bool) error {
+			return paginate(context.Background(), 5, pages(250, &sizes), fn)
+		})
+		Expect(err).To(BeNil())
+		Expect(items).To(Equal([]int{0, 1, 2, 3, 4}))
+		Expect(sizes).To(Equal([]int32{minPageSize}))
+
+		var seen []int
+		err = paginate(context.Background(), 0, pages(250, &sizes), func(i int) bool {
+			seen = append(seen, i)
+			return i < 150
+		})
+		Expect(err).To(BeNil())
+		Expect(seen).To(HaveLen(151))
+	})
+
+	It("should return the not found errors unless the listing ignores them", func() {
+		notFound := func(context.Context, *string, int32) ([]int, *string, error) {
+			return nil, nil, operationError("DescribeDBInstances", &types.DBInstanceNotFoundFault{})
+		}
+		err := paginate(context.Background(), 0, notFound, func(int) bool { return true })
+		Expect(err).To(MatchError(ErrNotFound))
+		Expect(ignoreNotFound(err)).To(BeNil())
+		Expect(ignoreNotFound(wrapError(operationError("DescribeDBInstances", &types.InvalidDBInstanceStateFault{})))).To(MatchError(ErrInvalidState))
+	})
+
+	It("should list a missing instance as empty but fail its log files", func() {
+		core := fakeClient("DBInstanceNotFound", "")
+		descs, err := newInstance(core).SetDBInstanceIdentifier("missing").List(context.Background())
+		Expect(err).To(BeNil())
+		Expect(descs).To(BeEmpty())
+
+		_, err = newInstance(core).SetDBInstanceIdentifier("missing").ListLogFiles(context.Background())
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+})
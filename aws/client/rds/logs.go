@@ -0,0 +1,224 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+const DefaultLogPollInterval = 5 * time.Second
+
+type LogFile struct {
+	DBInstanceIdentifier string
+	Name                 string
+	Size                 int64
+	LastWritten          time.Time
+}
+
+type LogStreamOptions struct {
+	// FromEnd starts at the latest portion of the file instead of its beginning.
+	FromEnd bool
+	// Follow waits for the lines written after the end of the file, as
+	// tail -f, until the reader is closed or the context is done.
+	Follow bool
+	// PollInterval is the delay between two polls at the end of the file,
+	// DefaultLogPollInterval by default.
+	PollInterval time.Duration
+	// NumberOfLines is the number of lines of each downloaded portion, 0 for
+	// portions of up to 1 MB.
+	NumberOfLines int32
+}
+
+// ListLogFiles returns the log files of the instance.
+func (s *rdsInstance) ListLogFiles(ctx context.Context) ([]LogFile, error) {
+	return listLogFiles(ctx, s.core, aws.ToString(s.describeInstanceParam.DBInstanceIdentifier))
+}
+
+// StreamLogFile returns the content of the log file file, of the instance
+// of the builder when file.DBInstanceIdentifier is empty.
+func (s *rdsInstance) StreamLogFile(ctx context.Context, file LogFile, opts *LogStreamOptions) (io.ReadCloser, error) {
+	if file.DBInstanceIdentifier == "" {
+		file.DBInstanceIdentifier = aws.ToString(s.describeInstanceParam.DBInstanceIdentifier)
+	}
+	return streamLogFile(ctx, s.core, file, opts)
+}
+
+// ListLogFiles returns the log files of every instance of the cluster.
+func (s *rdsAurora) ListLogFiles(ctx context.Context) ([]LogFile, error) {
+	instances, err := s.describeInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var files []LogFile
+	for _, ins := range instances {
+		insFiles, err := listLogFiles(ctx, s.core, ins.DBInstanceIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, insFiles...)
+	}
+	return files, nil
+}
+
+// StreamLogFile returns the content of the log file file of an instance of the cluster.
+func (s *rdsAurora) StreamLogFile(ctx context.Context, file LogFile, opts *LogStreamOptions) (io.ReadCloser, error) {
+	return streamLogFile(ctx, s.core, file, opts)
+}
+
+func listLogFiles(ctx context.Context, core *rds.Client, id string) ([]LogFile, error) {
+	if id == "" {
+		return nil, errors.New("db instance identifier is required")
+	}
+	return collect(func(fn func(LogFile) bool) error {
+		return paginate(ctx, 0, func(ctx context.Context, marker *string, pageSize int32) ([]LogFile, *string, error) {
+			out, err := core.DescribeDBLogFiles(ctx, &rds.DescribeDBLogFilesInput{
+				DBInstanceIdentifier: aws.String(id),
+				Marker:               marker,
+				MaxRecords:           aws.Int32(pageSize),
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			files := make([]LogFile, 0, len(out.DescribeDBLogFiles))
+			for _, f := range out.DescribeDBLogFiles {
+				files = append(files, LogFile{
+					DBInstanceIdentifier: id,
+					Name:                 aws.ToString(f.LogFileName),
+					Size:                 f.Size,
+					LastWritten:          time.UnixMilli(f.LastWritten),
+				})
+			}
+			return files, out.Marker, nil
+		}, fn)
+	})
+}
+
+func streamLogFile(ctx context.Context, core *rds.Client, file LogFile, opts *LogStreamOptions) (io.ReadCloser, error) {
+	if file.DBInstanceIdentifier == "" || file.Name == "" {
+		return nil, errors.New("db instance identifier and log file name are required")
+	}
+	if opts == nil {
+		opts = &LogStreamOptions{}
+	}
+	return newLogFileReader(ctx, opts, func(ctx context.Context, marker *string) (string, *string, bool, error) {
+		out, err := core.DownloadDBLogFilePortion(ctx, &rds.DownloadDBLogFilePortionInput{
+			DBInstanceIdentifier: aws.String(file.DBInstanceIdentifier),
+			LogFileName:          aws.String(file.Name),
+			Marker:               marker,
+			NumberOfLines:        opts.NumberOfLines,
+		})
+		if err != nil {
+			return "", nil, false, wrapError(err)
+		}
+		return aws.ToString(out.LogFileData), out.Marker, out.AdditionalDataPending, nil
+	}), nil
+}
+
+// logFileReader reads the portions of a log file returned by fetch, following
+// their markers.
+type logFileReader struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed atomic.Bool
+	fetch  func(ctx context.Context, marker *string) (data string, next *string, pending bool, err error)
+
+	follow   bool
+	interval time.Duration
+
+	marker  *string
+	buf     string
+	started bool
+	pending bool
+	empty   bool
+}
+
+func newLogFileReader(ctx context.Context, opts *LogStreamOptions, fetch func(context.Context, *string) (string, *string, bool, error)) *logFileReader {
+	r := &logFileReader{
+		fetch:    fetch,
+		follow:   opts.Follow,
+		interval: opts.PollInterval,
+	}
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	if r.interval <= 0 {
+		r.interval = DefaultLogPollInterval
+	}
+	if !opts.FromEnd {
+		r.marker = aws.String("0")
+	}
+	return r
+}
+
+// Read returns io.EOF at the end of the file unless following it, and
+// os.ErrClosed once the reader is closed.
+func (r *logFileReader) Read(p []byte) (int, error) {
+	for r.buf == "" {
+		if r.ctx.Err() != nil {
+			return 0, r.stopped()
+		}
+		if r.started && !r.pending && !r.follow {
+			return 0, io.EOF
+		}
+		// Poll at the end of the file, and after an empty portion which
+		// still announces pending data, rather than calling the api in a loop.
+		if r.started && (!r.pending || r.empty) {
+			timer := time.NewTimer(r.interval)
+			select {
+			case <-r.ctx.Done():
+				timer.Stop()
+				return 0, r.stopped()
+			case <-timer.C:
+			}
+		}
+
+		data, next, pending, err := r.fetch(r.ctx, r.marker)
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return 0, r.stopped()
+			}
+			return 0, err
+		}
+		r.started, r.pending, r.empty, r.buf = true, pending, data == "", data
+		if next != nil {
+			r.marker = next
+		}
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// stopped returns the error of a reader whose context is done.
+func (r *logFileReader) stopped() error {
+	if r.closed.Load() {
+		return os.ErrClosed
+	}
+	return r.ctx.Err()
+}
+
+// Close stops the reader, a following reader included.
+func (r *logFileReader) Close() error {
+	r.closed.Store(true)
+	r.cancel()
+	return nil
+}
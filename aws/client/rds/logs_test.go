@@ -0,0 +1,126 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type logPortion struct {
+	data    string
+	next    string
+	pending bool
+}
+
+func fakeLogFetch(markers *[]string, portions map[string]logPortion) func(context.Context, *string) (string, *string, bool, error) {
+	return func(_ context.Context, marker *string) (string, *string, bool, error) {
+		m := aws.ToString(marker)
+		*markers = append(*markers, m)
+		p := portions[m]
+		return p.data, aws.String(p.next), p.pending, nil
+	}
+}
+
+var _ = Describe("Logs", func() {
+	portions := map[string]logPortion{
+		"0":   {data: "line 1\n", next: "1:7", pending: true},
+		"1:7": {data: "line 2\n", next: "1:14"},
+		"":    {data: "line 2\n", next: "1:14"},
+	}
+
+	It("should read the log file from its beginning following the markers", func() {
+		var markers []string
+		r := newLogFileReader(context.Background(), &LogStreamOptions{}, fakeLogFetch(&markers, portions))
+		data, err := io.ReadAll(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("line 1\nline 2\n"))
+		Expect(markers).To(Equal([]string{"0", "1:7"}))
+		Expect(r.Close()).To(Succeed())
+	})
+
+	It("should read the latest portion of the log file", func() {
+		var markers []string
+		r := newLogFileReader(context.Background(), &LogStreamOptions{FromEnd: true}, fakeLogFetch(&markers, portions))
+		data, err := io.ReadAll(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("line 2\n"))
+		Expect(markers).To(Equal([]string{""}))
+	})
+
+	It("should follow the log file until it is closed", func() {
+		var markers []string
+		portions := map[string]logPortion{
+			"":     {data: "line 2\n", next: "1:14"},
+			"1:14": {data: "line 3\n", next: "1:21"},
+			"1:21": {next: "1:21"},
+		}
+		r := newLogFileReader(context.Background(), &LogStreamOptions{FromEnd: true, Follow: true, PollInterval: time.Millisecond}, fakeLogFetch(&markers, portions))
+
+		buf := make([]byte, 64)
+		n, err := r.Read(buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buf[:n])).To(Equal("line 2\n"))
+		n, err = r.Read(buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(buf[:n])).To(Equal("line 3\n"))
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			r.Close()
+		}()
+		_, err = r.Read(buf)
+		Expect(err).To(MatchError(os.ErrClosed))
+		_, err = r.Read(buf)
+		Expect(err).To(MatchError(os.ErrClosed))
+		Expect(markers[:3]).To(Equal([]string{"", "1:14", "1:21"}))
+	})
+
+	It("should pause after an empty portion announcing pending data", func() {
+		var markers []string
+		portions := map[string]logPortion{
+			"0":   {next: "1:0", pending: true},
+			"1:0": {next: "1:1", pending: true},
+			"1:1": {data: "line 1\n", next: "1:8"},
+		}
+		start := time.Now()
+		r := newLogFileReader(context.Background(), &LogStreamOptions{PollInterval: 20 * time.Millisecond}, fakeLogFetch(&markers, portions))
+		data, err := io.ReadAll(r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("line 1\n"))
+		Expect(markers).To(Equal([]string{"0", "1:0", "1:1"}))
+		Expect(time.Since(start)).To(BeNumerically(">=", 40*time.Millisecond))
+	})
+
+	It("should fail to read once closed", func() {
+		var markers []string
+		r := newLogFileReader(context.Background(), &LogStreamOptions{}, fakeLogFetch(&markers, portions))
+		Expect(r.Close()).To(Succeed())
+		_, err := r.Read(make([]byte, 8))
+		Expect(err).To(MatchError(os.ErrClosed))
+		Expect(markers).To(BeEmpty())
+	})
+
+	It("should require the log file to stream", func() {
+		_, err := newInstance(nil).StreamLogFile(context.Background(), LogFile{Name: "error/mysql-error.log"}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
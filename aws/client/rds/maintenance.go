@@ -0,0 +1,152 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+type MaintenanceOptIn string
+
+const (
+	// MaintenanceOptInImmediate applies the action immediately.
+	MaintenanceOptInImmediate MaintenanceOptIn = "immediate"
+	// MaintenanceOptInNextWindow applies the action in the next maintenance window.
+	MaintenanceOptInNextWindow MaintenanceOptIn = "next-maintenance"
+	// MaintenanceOptInUndo cancels a previous opt-in.
+	MaintenanceOptInUndo MaintenanceOptIn = "undo-opt-in"
+
+	MaintenanceActionSystemUpdate   = "system-update"
+	MaintenanceActionDBUpgrade      = "db-upgrade"
+	MaintenanceActionHardwareUpdate = "hardware-maintenance"
+	MaintenanceActionCACertRotation = "ca-certificate-rotation"
+)
+
+// PendingMaintenanceAction is a maintenance action waiting for an instance or a cluster.
+type PendingMaintenanceAction struct {
+	ResourceArn          string
+	Action               string
+	Description          string
+	OptInStatus          string
+	AutoAppliedAfterDate time.Time
+	ForcedApplyDate      time.Time
+	CurrentApplyDate     time.Time
+}
+
+// Forced reports whether the action is applied at ForcedApplyDate regardless
+// of the maintenance window and the opt-ins.
+func (a *PendingMaintenanceAction) Forced() bool {
+	return !a.ForcedApplyDate.IsZero()
+}
+
+// Maintenance lists and applies the pending maintenance actions of the
+// instances and clusters.
+type Maintenance interface {
+	// SetResourceArn sets the ARN of the instance or cluster.
+	SetResourceArn(arn string) Maintenance
+	// SetFilter filters the resources by db-instance-id or db-cluster-id,
+	// which accept identifiers and ARNs.
+	SetFilter(name string, values []string) Maintenance
+	SetMaxResults(max int32) Maintenance
+
+	// PendingMaintenance returns the pending actions of the resource, or of
+	// every instance and cluster matching the filters when it is not set.
+	PendingMaintenance(context.Context) ([]*PendingMaintenanceAction, error)
+	// ApplyMaintenance opts the resource in action.
+	ApplyMaintenance(ctx context.Context, action string, optIn MaintenanceOptIn) error
+}
+
+type rdsMaintenance struct {
+	core *rds.Client
+
+	describePendingParam *rds.DescribePendingMaintenanceActionsInput
+	applyPendingParam    *rds.ApplyPendingMaintenanceActionInput
+
+	maxResults int32
+}
+
+func newMaintenance(core *rds.Client) *rdsMaintenance {
+	return &rdsMaintenance{
+		core:                 core,
+		describePendingParam: &rds.DescribePendingMaintenanceActionsInput{},
+		applyPendingParam:    &rds.ApplyPendingMaintenanceActionInput{},
+	}
+}
+
+func (s *rdsMaintenance) SetResourceArn(arn string) Maintenance {
+	s.describePendingParam.ResourceIdentifier = aws.String(arn)
+	s.applyPendingParam.ResourceIdentifier = aws.String(arn)
+	return s
+}
+
+func (s *rdsMaintenance) SetFilter(name string, values []string) Maintenance {
+	s.describePendingParam.Filters = setFilter(s.describePendingParam.Filters, name, values)
+	return s
+}
+
+func (s *rdsMaintenance) SetMaxResults(max int32) Maintenance {
+	s.maxResults = max
+	return s
+}
+
+func (s *rdsMaintenance) PendingMaintenance(ctx context.Context) ([]*PendingMaintenanceAction, error) {
+	return collect(func(fn func(*PendingMaintenanceAction) bool) error {
+		return paginate(ctx, s.maxResults, func(ctx context.Context, marker *string, pageSize int32) ([]*PendingMaintenanceAction, *string, error) {
+			param := *s.describePendingParam
+			param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+			out, err := s.core.DescribePendingMaintenanceActions(ctx, &param)
+			if err != nil {
+				return nil, nil, err
+			}
+			var actions []*PendingMaintenanceAction
+			for i := range out.PendingMaintenanceActions {
+				actions = append(actions, convertPendingMaintenanceActions(&out.PendingMaintenanceActions[i])...)
+			}
+			return actions, out.Marker, nil
+		}, fn)
+	})
+}
+
+func (s *rdsMaintenance) ApplyMaintenance(ctx context.Context, action string, optIn MaintenanceOptIn) error {
+	if s.applyPendingParam.ResourceIdentifier == nil {
+		return errors.New("resource arn is required")
+	}
+	param := *s.applyPendingParam
+	param.ApplyAction, param.OptInType = aws.String(action), aws.String(string(optIn))
+	_, err := s.core.ApplyPendingMaintenanceAction(ctx, &param)
+	return wrapError(err)
+}
+
+func convertPendingMaintenanceActions(in *types.ResourcePendingMaintenanceActions) []*PendingMaintenanceAction {
+	actions := make([]*PendingMaintenanceAction, 0, len(in.PendingMaintenanceActionDetails))
+	for _, a := range in.PendingMaintenanceActionDetails {
+		actions = append(actions, &PendingMaintenanceAction{
+			ResourceArn:          aws.ToString(in.ResourceIdentifier),
+			Action:               aws.ToString(a.Action),
+			Description:          aws.ToString(a.Description),
+			OptInStatus:          aws.ToString(a.OptInStatus),
+			AutoAppliedAfterDate: aws.ToTime(a.AutoAppliedAfterDate),
+			ForcedApplyDate:      aws.ToTime(a.ForcedApplyDate),
+			CurrentApplyDate:     aws.ToTime(a.CurrentApplyDate),
+		})
+	}
+	return actions
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maintenance", func() {
+	It("should convert the pending actions of a resource", func() {
+		forced := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+		actions := convertPendingMaintenanceActions(&types.ResourcePendingMaintenanceActions{
+			ResourceIdentifier: aws.String("arn:aws:rds:us-east-1:123456789012:db:test-instance"),
+			PendingMaintenanceActionDetails: []types.PendingMaintenanceAction{
+				{Action: aws.String(MaintenanceActionSystemUpdate), Description: aws.String("New Operating System update is available")},
+				{Action: aws.String(MaintenanceActionCACertRotation), ForcedApplyDate: aws.Time(forced), OptInStatus: aws.String("pending")},
+			},
+		})
+		Expect(actions).To(HaveLen(2))
+		Expect(actions[0].ResourceArn).To(Equal("arn:aws:rds:us-east-1:123456789012:db:test-instance"))
+		Expect(actions[0].Action).To(Equal(MaintenanceActionSystemUpdate))
+		Expect(actions[0].Forced()).To(BeFalse())
+		Expect(actions[1].Forced()).To(BeTrue())
+		Expect(actions[1].ForcedApplyDate).To(Equal(forced))
+		Expect(actions[1].OptInStatus).To(Equal("pending"))
+	})
+
+	It("should feed the resource and the filters to the params", func() {
+		m := newMaintenance(nil)
+		m.SetResourceArn("arn:aws:rds:us-east-1:123456789012:cluster:test-cluster").SetFilter("db-cluster-id", []string{"test-cluster"})
+		Expect(aws.ToString(m.describePendingParam.ResourceIdentifier)).To(Equal("arn:aws:rds:us-east-1:123456789012:cluster:test-cluster"))
+		Expect(aws.ToString(m.applyPendingParam.ResourceIdentifier)).To(Equal("arn:aws:rds:us-east-1:123456789012:cluster:test-cluster"))
+		Expect(m.describePendingParam.Filters).To(HaveLen(1))
+	})
+
+	It("should require the resource to apply an action", func() {
+		Expect(newMaintenance(nil).ApplyMaintenance(context.Background(), MaintenanceActionSystemUpdate, MaintenanceOptInImmediate)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,259 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+type DescOptionGroup struct {
+	Name               string
+	Arn                string
+	Description        string
+	EngineName         string
+	MajorEngineVersion string
+	VpcId              string
+	Options            []DescOption
+}
+
+type DescOption struct {
+	Name       string
+	Version    string
+	Port       int32
+	Persistent bool
+	Permanent  bool
+	Settings   map[string]string
+}
+
+// OptionConfiguration is an option added to a group by Modify, such as
+// MARIADB_AUDIT_PLUGIN or SQLSERVER_BACKUP_RESTORE.
+type OptionConfiguration struct {
+	Name                string
+	Version             string
+	Port                int32
+	VpcSecurityGroupIds []string
+	Settings            map[string]string
+}
+
+// OptionGroupStatus is the membership of an instance in an option group.
+type OptionGroupStatus struct {
+	Name   string
+	Status string
+}
+
+// OptionGroup manages the option groups used by SetOptionGroupName of
+// Instance and Cluster.
+type OptionGroup interface {
+	SetName(name string) OptionGroup
+	SetDescription(desc string) OptionGroup
+	SetEngineName(engine string) OptionGroup
+	SetMajorEngineVersion(version string) OptionGroup
+	SetOption(option OptionConfiguration) OptionGroup
+	SetRemoveOption(name string) OptionGroup
+	SetApplyImmediately(enable bool) OptionGroup
+	SetMaxResults(max int32) OptionGroup
+
+	Create(context.Context) error
+	// Modify adds the options set by SetOption and removes the ones set by
+	// SetRemoveOption.
+	Modify(context.Context) error
+	Delete(context.Context) error
+	Describe(context.Context) (*DescOptionGroup, error)
+	List(context.Context) ([]*DescOptionGroup, error)
+}
+
+type rdsOptionGroup struct {
+	core *rds.Client
+
+	createOptionGroupParam    *rds.CreateOptionGroupInput
+	modifyOptionGroupParam    *rds.ModifyOptionGroupInput
+	deleteOptionGroupParam    *rds.DeleteOptionGroupInput
+	describeOptionGroupsParam *rds.DescribeOptionGroupsInput
+
+	maxResults int32
+}
+
+func newOptionGroup(core *rds.Client) *rdsOptionGroup {
+	return &rdsOptionGroup{
+		core:                      core,
+		createOptionGroupParam:    &rds.CreateOptionGroupInput{},
+		modifyOptionGroupParam:    &rds.ModifyOptionGroupInput{},
+		deleteOptionGroupParam:    &rds.DeleteOptionGroupInput{},
+		describeOptionGroupsParam: &rds.DescribeOptionGroupsInput{},
+	}
+}
+
+func (s *rdsOptionGroup) SetName(name string) OptionGroup {
+	s.createOptionGroupParam.OptionGroupName = aws.String(name)
+	s.modifyOptionGroupParam.OptionGroupName = aws.String(name)
+	s.deleteOptionGroupParam.OptionGroupName = aws.String(name)
+	s.describeOptionGroupsParam.OptionGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsOptionGroup) SetDescription(desc string) OptionGroup {
+	s.createOptionGroupParam.OptionGroupDescription = aws.String(desc)
+	return s
+}
+
+func (s *rdsOptionGroup) SetEngineName(engine string) OptionGroup {
+	s.createOptionGroupParam.EngineName = aws.String(engine)
+	s.describeOptionGroupsParam.EngineName = aws.String(engine)
+	return s
+}
+
+func (s *rdsOptionGroup) SetMajorEngineVersion(version string) OptionGroup {
+	s.createOptionGroupParam.MajorEngineVersion = aws.String(version)
+	s.describeOptionGroupsParam.MajorEngineVersion = aws.String(version)
+	return s
+}
+
+func (s *rdsOptionGroup) SetOption(option OptionConfiguration) OptionGroup {
+	s.modifyOptionGroupParam.OptionsToInclude = append(s.modifyOptionGroupParam.OptionsToInclude, convertOptionConfiguration(option))
+	return s
+}
+
+func (s *rdsOptionGroup) SetRemoveOption(name string) OptionGroup {
+	s.modifyOptionGroupParam.OptionsToRemove = append(s.modifyOptionGroupParam.OptionsToRemove, name)
+	return s
+}
+
+// SetApplyImmediately applies Modify to the member instances now instead of
+// during their next maintenance window.
+func (s *rdsOptionGroup) SetApplyImmediately(enable bool) OptionGroup {
+	s.modifyOptionGroupParam.ApplyImmediately = enable
+	return s
+}
+
+// SetMaxResults caps the number of groups returned by List, 0 for no cap.
+func (s *rdsOptionGroup) SetMaxResults(max int32) OptionGroup {
+	s.maxResults = max
+	return s
+}
+
+func (s *rdsOptionGroup) Create(ctx context.Context) error {
+	_, err := s.core.CreateOptionGroup(ctx, s.createOptionGroupParam)
+	return wrapError(err)
+}
+
+func (s *rdsOptionGroup) Modify(ctx context.Context) error {
+	_, err := s.core.ModifyOptionGroup(ctx, s.modifyOptionGroupParam)
+	return wrapError(err)
+}
+
+func (s *rdsOptionGroup) Delete(ctx context.Context) error {
+	_, err := s.core.DeleteOptionGroup(ctx, s.deleteOptionGroupParam)
+	return wrapError(err)
+}
+
+// Describe returns the group set by SetName, nil when it does not exist.
+func (s *rdsOptionGroup) Describe(ctx context.Context) (*DescOptionGroup, error) {
+	if s.describeOptionGroupsParam.OptionGroupName == nil {
+		return nil, errors.New("option group name is required")
+	}
+	groups, err := s.List(ctx)
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	return groups[0], nil
+}
+
+// List returns the groups matching the name, the engine and its major version.
+func (s *rdsOptionGroup) List(ctx context.Context) ([]*DescOptionGroup, error) {
+	return collect(func(fn func(*DescOptionGroup) bool) error {
+		return ignoreNotFound(paginate(ctx, s.maxResults, func(ctx context.Context, marker *string, pageSize int32) ([]*DescOptionGroup, *string, error) {
+			param := *s.describeOptionGroupsParam
+			param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+			out, err := s.core.DescribeOptionGroups(ctx, &param)
+			if err != nil {
+				return nil, nil, err
+			}
+			var descs []*DescOptionGroup
+			for i := range out.OptionGroupsList {
+				descs = append(descs, convertOptionGroup(&out.OptionGroupsList[i]))
+			}
+			return descs, out.Marker, nil
+		}, fn))
+	})
+}
+
+func convertOptionConfiguration(in OptionConfiguration) types.OptionConfiguration {
+	out := types.OptionConfiguration{
+		OptionName:                  aws.String(in.Name),
+		VpcSecurityGroupMemberships: in.VpcSecurityGroupIds,
+	}
+	if in.Version != "" {
+		out.OptionVersion = aws.String(in.Version)
+	}
+	if in.Port > 0 {
+		out.Port = aws.Int32(in.Port)
+	}
+
+	names := make([]string, 0, len(in.Settings))
+	for name := range in.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.OptionSettings = append(out.OptionSettings, types.OptionSetting{
+			Name:  aws.String(name),
+			Value: aws.String(in.Settings[name]),
+		})
+	}
+	return out
+}
+
+func convertOptionGroup(in *types.OptionGroup) *DescOptionGroup {
+	desc := &DescOptionGroup{
+		Name:               aws.ToString(in.OptionGroupName),
+		Arn:                aws.ToString(in.OptionGroupArn),
+		Description:        aws.ToString(in.OptionGroupDescription),
+		EngineName:         aws.ToString(in.EngineName),
+		MajorEngineVersion: aws.ToString(in.MajorEngineVersion),
+		VpcId:              aws.ToString(in.VpcId),
+	}
+	for _, option := range in.Options {
+		o := DescOption{
+			Name:       aws.ToString(option.OptionName),
+			Version:    aws.ToString(option.OptionVersion),
+			Port:       aws.ToInt32(option.Port),
+			Persistent: option.Persistent,
+			Permanent:  option.Permanent,
+			Settings:   map[string]string{},
+		}
+		for _, setting := range option.OptionSettings {
+			o.Settings[aws.ToString(setting.Name)] = aws.ToString(setting.Value)
+		}
+		desc.Options = append(desc.Options, o)
+	}
+	return desc
+}
+
+func convertOptionGroupStatus(in []types.OptionGroupMembership) []OptionGroupStatus {
+	var out []OptionGroupStatus
+	for _, m := range in {
+		out = append(out, OptionGroupStatus{
+			Name:   aws.ToString(m.OptionGroupName),
+			Status: aws.ToString(m.Status),
+		})
+	}
+	return out
+}
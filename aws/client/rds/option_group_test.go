@@ -0,0 +1,83 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SubnetAndOptionGroup", func() {
+	It("should feed the subnet group inputs from the setters", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).SubnetGroup().
+			SetName("private").
+			SetSubnetIds([]string{"subnet-a", "subnet-b"}).(*rdsSubnetGroup)
+
+		Expect(aws.ToString(b.deleteSubnetGroupParam.DBSubnetGroupName)).To(Equal("private"))
+		Expect(b.modifySubnetGroupParam.SubnetIds).To(Equal([]string{"subnet-a", "subnet-b"}))
+
+		desc := convertSubnetGroup(&types.DBSubnetGroup{
+			DBSubnetGroupName: aws.String("private"),
+			Subnets: []types.Subnet{{
+				SubnetIdentifier:       aws.String("subnet-a"),
+				SubnetAvailabilityZone: &types.AvailabilityZone{Name: aws.String("us-east-1a")},
+			}, {
+				SubnetIdentifier: aws.String("subnet-b"),
+			}},
+		})
+		Expect(desc.Subnets).To(Equal([]Subnet{
+			{Identifier: "subnet-a", AvailabilityZone: "us-east-1a"},
+			{Identifier: "subnet-b"},
+		}))
+	})
+
+	It("should add and remove options with their settings", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).OptionGroup().
+			SetName("mysql-audit").
+			SetOption(OptionConfiguration{
+				Name:     "MARIADB_AUDIT_PLUGIN",
+				Settings: map[string]string{"SERVER_AUDIT_EVENTS": "CONNECT,QUERY", "SERVER_AUDIT_FILE_ROTATIONS": "10"},
+			}).
+			SetRemoveOption("MEMCACHED").(*rdsOptionGroup)
+
+		include := b.modifyOptionGroupParam.OptionsToInclude
+		Expect(include).To(HaveLen(1))
+		Expect(include[0].Port).To(BeNil())
+		Expect(aws.ToString(include[0].OptionSettings[0].Name)).To(Equal("SERVER_AUDIT_EVENTS"))
+		Expect(aws.ToString(include[0].OptionSettings[1].Value)).To(Equal("10"))
+		Expect(b.modifyOptionGroupParam.OptionsToRemove).To(Equal([]string{"MEMCACHED"}))
+	})
+
+	It("should attach the groups on create, restore and modify", func() {
+		i := newInstance(nil)
+		i.SetOptionGroupName("mysql-audit")
+		Expect(aws.ToString(i.createInstanceParam.OptionGroupName)).To(Equal("mysql-audit"))
+		Expect(aws.ToString(i.restoreFromSnapshotParam.OptionGroupName)).To(Equal("mysql-audit"))
+		Expect(aws.ToString(i.modifyInstanceParam.OptionGroupName)).To(Equal("mysql-audit"))
+
+		c := newCluster(nil)
+		c.SetOptionGroupName("mysql-audit")
+		Expect(aws.ToString(c.restoreDBClusterPitrParam.OptionGroupName)).To(Equal("mysql-audit"))
+
+		desc := convertDBInstance(&types.DBInstance{
+			DBSubnetGroup:          &types.DBSubnetGroup{DBSubnetGroupName: aws.String("private")},
+			OptionGroupMemberships: []types.OptionGroupMembership{{OptionGroupName: aws.String("mysql-audit"), Status: aws.String("pending-apply")}},
+		})
+		Expect(desc.DBSubnetGroupName).To(Equal("private"))
+		Expect(desc.OptionGroups).To(Equal([]OptionGroupStatus{{Name: "mysql-audit", Status: "pending-apply"}}))
+	})
+})
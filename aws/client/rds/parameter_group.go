@@ -0,0 +1,476 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+type ApplyMethod string
+
+const (
+	ApplyMethodImmediate     ApplyMethod = "immediate"
+	ApplyMethodPendingReboot ApplyMethod = "pending-reboot"
+)
+
+const (
+	ParameterSourceUser          = "user"
+	ParameterSourceSystem        = "system"
+	ParameterSourceEngineDefault = "engine-default"
+
+	// maxModifyParameters is the number of parameters accepted by one
+	// modify or reset call.
+	maxModifyParameters = 20
+)
+
+type DescParameterGroup struct {
+	Name        string
+	Arn         string
+	Family      string
+	Description string
+	Cluster     bool
+}
+
+type Parameter struct {
+	Name                 string
+	Value                string
+	ApplyMethod          ApplyMethod
+	ApplyType            string
+	DataType             string
+	AllowedValues        string
+	Description          string
+	Source               string
+	MinimumEngineVersion string
+	IsModifiable         bool
+}
+
+// ParameterDiff is a parameter whose value differs, From and To are empty
+// when the parameter is unset on their side.
+type ParameterDiff struct {
+	Name string
+	From string
+	To   string
+}
+
+// ParameterGroup manages a DB parameter group, or a DB cluster parameter
+// group with SetCluster.
+type ParameterGroup interface {
+	SetName(name string) ParameterGroup
+	SetFamily(family string) ParameterGroup
+	SetDescription(desc string) ParameterGroup
+	SetCluster(enable bool) ParameterGroup
+	SetSourceName(name string) ParameterGroup
+	SetSource(source string) ParameterGroup
+	SetParameter(name, value string, method ApplyMethod) ParameterGroup
+
+	Create(context.Context) error
+	// Copy copies the group set by SetSourceName to the one set by SetName.
+	Copy(context.Context) error
+	Delete(context.Context) error
+	Describe(context.Context) (*DescParameterGroup, error)
+	// GetParameters returns the parameters of the group, ErrNotFound when it
+	// does not exist.
+	GetParameters(context.Context) ([]Parameter, error)
+	// Modify applies the parameters set by SetParameter.
+	Modify(context.Context) error
+	// Reset resets the parameters set by SetParameter to their engine
+	// defaults, or every parameter when none is set.
+	Reset(context.Context) error
+	// DescribeEngineDefaults returns the default parameters of the family.
+	DescribeEngineDefaults(context.Context) ([]Parameter, error)
+	// Diff compares the parameters of the group with the ones of the group
+	// other, both restricted by SetSource, ErrNotFound when either does not
+	// exist.
+	Diff(ctx context.Context, other string) ([]ParameterDiff, error)
+	// DiffDesired compares the parameters of the group with desired.
+	DiffDesired(ctx context.Context, desired map[string]string) ([]ParameterDiff, error)
+}
+
+type rdsParameterGroup struct {
+	core *rds.Client
+
+	cluster    bool
+	parameters []types.Parameter
+
+	createParam          *rds.CreateDBParameterGroupInput
+	copyParam            *rds.CopyDBParameterGroupInput
+	describeParam        *rds.DescribeDBParametersInput
+	createClusterParam   *rds.CreateDBClusterParameterGroupInput
+	copyClusterParam     *rds.CopyDBClusterParameterGroupInput
+	describeClusterParam *rds.DescribeDBClusterParametersInput
+}
+
+func newParameterGroup(core *rds.Client) *rdsParameterGroup {
+	return &rdsParameterGroup{
+		core:                 core,
+		createParam:          &rds.CreateDBParameterGroupInput{},
+		copyParam:            &rds.CopyDBParameterGroupInput{},
+		describeParam:        &rds.DescribeDBParametersInput{},
+		createClusterParam:   &rds.CreateDBClusterParameterGroupInput{},
+		copyClusterParam:     &rds.CopyDBClusterParameterGroupInput{},
+		describeClusterParam: &rds.DescribeDBClusterParametersInput{},
+	}
+}
+
+func (s *rdsParameterGroup) SetName(name string) ParameterGroup {
+	s.createParam.DBParameterGroupName = aws.String(name)
+	s.copyParam.TargetDBParameterGroupIdentifier = aws.String(name)
+	s.describeParam.DBParameterGroupName = aws.String(name)
+	s.createClusterParam.DBClusterParameterGroupName = aws.String(name)
+	s.copyClusterParam.TargetDBClusterParameterGroupIdentifier = aws.String(name)
+	s.describeClusterParam.DBClusterParameterGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsParameterGroup) SetFamily(family string) ParameterGroup {
+	s.createParam.DBParameterGroupFamily = aws.String(family)
+	s.createClusterParam.DBParameterGroupFamily = aws.String(family)
+	return s
+}
+
+func (s *rdsParameterGroup) SetDescription(desc string) ParameterGroup {
+	s.createParam.Description = aws.String(desc)
+	s.copyParam.TargetDBParameterGroupDescription = aws.String(desc)
+	s.createClusterParam.Description = aws.String(desc)
+	s.copyClusterParam.TargetDBClusterParameterGroupDescription = aws.String(desc)
+	return s
+}
+
+// SetCluster switches the builder to DB cluster parameter groups.
+func (s *rdsParameterGroup) SetCluster(enable bool) ParameterGroup {
+	s.cluster = enable
+	return s
+}
+
+// SetSourceName sets the name or ARN of the group copied by Copy.
+func (s *rdsParameterGroup) SetSourceName(name string) ParameterGroup {
+	s.copyParam.SourceDBParameterGroupIdentifier = aws.String(name)
+	s.copyClusterParam.SourceDBClusterParameterGroupIdentifier = aws.String(name)
+	return s
+}
+
+// SetSource restricts GetParameters to one ParameterSourceXxx.
+func (s *rdsParameterGroup) SetSource(source string) ParameterGroup {
+	s.describeParam.Source = aws.String(source)
+	s.describeClusterParam.Source = aws.String(source)
+	return s
+}
+
+func (s *rdsParameterGroup) SetParameter(name, value string, method ApplyMethod) ParameterGroup {
+	s.parameters = append(s.parameters, types.Parameter{
+		ParameterName:  aws.String(name),
+		ParameterValue: aws.String(value),
+		ApplyMethod:    types.ApplyMethod(method),
+	})
+	return s
+}
+
+func (s *rdsParameterGroup) Create(ctx context.Context) error {
+	var err error
+	if s.cluster {
+		_, err = s.core.CreateDBClusterParameterGroup(ctx, s.createClusterParam)
+	} else {
+		_, err = s.core.CreateDBParameterGroup(ctx, s.createParam)
+	}
+	return wrapError(err)
+}
+
+func (s *rdsParameterGroup) Copy(ctx context.Context) error {
+	var err error
+	if s.cluster {
+		_, err = s.core.CopyDBClusterParameterGroup(ctx, s.copyClusterParam)
+	} else {
+		_, err = s.core.CopyDBParameterGroup(ctx, s.copyParam)
+	}
+	return wrapError(err)
+}
+
+func (s *rdsParameterGroup) Delete(ctx context.Context) error {
+	var err error
+	if s.cluster {
+		_, err = s.core.DeleteDBClusterParameterGroup(ctx, &rds.DeleteDBClusterParameterGroupInput{
+			DBClusterParameterGroupName: s.describeClusterParam.DBClusterParameterGroupName,
+		})
+	} else {
+		_, err = s.core.DeleteDBParameterGroup(ctx, &rds.DeleteDBParameterGroupInput{
+			DBParameterGroupName: s.describeParam.DBParameterGroupName,
+		})
+	}
+	return wrapError(err)
+}
+
+// Describe returns the group, nil when it does not exist.
+func (s *rdsParameterGroup) Describe(ctx context.Context) (*DescParameterGroup, error) {
+	var (
+		desc *DescParameterGroup
+		err  error
+	)
+	if s.cluster {
+		var out *rds.DescribeDBClusterParameterGroupsOutput
+		out, err = s.core.DescribeDBClusterParameterGroups(ctx, &rds.DescribeDBClusterParameterGroupsInput{
+			DBClusterParameterGroupName: s.describeClusterParam.DBClusterParameterGroupName,
+		})
+		if err == nil && len(out.DBClusterParameterGroups) > 0 {
+			g := out.DBClusterParameterGroups[0]
+			desc = &DescParameterGroup{
+				Name:        aws.ToString(g.DBClusterParameterGroupName),
+				Arn:         aws.ToString(g.DBClusterParameterGroupArn),
+				Family:      aws.ToString(g.DBParameterGroupFamily),
+				Description: aws.ToString(g.Description),
+				Cluster:     true,
+			}
+		}
+	} else {
+		var out *rds.DescribeDBParameterGroupsOutput
+		out, err = s.core.DescribeDBParameterGroups(ctx, &rds.DescribeDBParameterGroupsInput{
+			DBParameterGroupName: s.describeParam.DBParameterGroupName,
+		})
+		if err == nil && len(out.DBParameterGroups) > 0 {
+			g := out.DBParameterGroups[0]
+			desc = &DescParameterGroup{
+				Name:        aws.ToString(g.DBParameterGroupName),
+				Arn:         aws.ToString(g.DBParameterGroupArn),
+				Family:      aws.ToString(g.DBParameterGroupFamily),
+				Description: aws.ToString(g.Description),
+			}
+		}
+	}
+	if err = wrapError(err); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return desc, nil
+}
+
+func (s *rdsParameterGroup) GetParameters(ctx context.Context) ([]Parameter, error) {
+	return collect(func(fn func(Parameter) bool) error {
+		return paginate(ctx, 0, func(ctx context.Context, marker *string, pageSize int32) ([]Parameter, *string, error) {
+			if s.cluster {
+				param := *s.describeClusterParam
+				param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+				out, err := s.core.DescribeDBClusterParameters(ctx, &param)
+				if err != nil {
+					return nil, nil, err
+				}
+				return convertParameters(out.Parameters), out.Marker, nil
+			}
+			param := *s.describeParam
+			param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+			out, err := s.core.DescribeDBParameters(ctx, &param)
+			if err != nil {
+				return nil, nil, err
+			}
+			return convertParameters(out.Parameters), out.Marker, nil
+		}, fn)
+	})
+}
+
+func (s *rdsParameterGroup) Modify(ctx context.Context) error {
+	if len(s.parameters) == 0 {
+		return errors.New("no parameter to modify")
+	}
+	for _, batch := range batchParameters(s.parameters) {
+		var err error
+		if s.cluster {
+			_, err = s.core.ModifyDBClusterParameterGroup(ctx, &rds.ModifyDBClusterParameterGroupInput{
+				DBClusterParameterGroupName: s.describeClusterParam.DBClusterParameterGroupName,
+				Parameters:                  batch,
+			})
+		} else {
+			_, err = s.core.ModifyDBParameterGroup(ctx, &rds.ModifyDBParameterGroupInput{
+				DBParameterGroupName: s.describeParam.DBParameterGroupName,
+				Parameters:           batch,
+			})
+		}
+		if err != nil {
+			return wrapError(err)
+		}
+	}
+	return nil
+}
+
+func (s *rdsParameterGroup) Reset(ctx context.Context) error {
+	batches := batchParameters(s.parameters)
+	if len(batches) == 0 {
+		batches = [][]types.Parameter{nil}
+	}
+	for _, batch := range batches {
+		var err error
+		if s.cluster {
+			_, err = s.core.ResetDBClusterParameterGroup(ctx, &rds.ResetDBClusterParameterGroupInput{
+				DBClusterParameterGroupName: s.describeClusterParam.DBClusterParameterGroupName,
+				Parameters:                  batch,
+				ResetAllParameters:          batch == nil,
+			})
+		} else {
+			_, err = s.core.ResetDBParameterGroup(ctx, &rds.ResetDBParameterGroupInput{
+				DBParameterGroupName: s.describeParam.DBParameterGroupName,
+				Parameters:           batch,
+				ResetAllParameters:   batch == nil,
+			})
+		}
+		if err != nil {
+			return wrapError(err)
+		}
+	}
+	return nil
+}
+
+func (s *rdsParameterGroup) DescribeEngineDefaults(ctx context.Context) ([]Parameter, error) {
+	family := s.createParam.DBParameterGroupFamily
+	if family == nil {
+		return nil, errors.New("db parameter group family is required")
+	}
+	return collect(func(fn func(Parameter) bool) error {
+		return paginate(ctx, 0, func(ctx context.Context, marker *string, pageSize int32) ([]Parameter, *string, error) {
+			var (
+				defaults *types.EngineDefaults
+				err      error
+			)
+			if s.cluster {
+				var out *rds.DescribeEngineDefaultClusterParametersOutput
+				out, err = s.core.DescribeEngineDefaultClusterParameters(ctx, &rds.DescribeEngineDefaultClusterParametersInput{
+					DBParameterGroupFamily: family,
+					Marker:                 marker,
+					MaxRecords:             aws.Int32(pageSize),
+				})
+				if err == nil {
+					defaults = out.EngineDefaults
+				}
+			} else {
+				var out *rds.DescribeEngineDefaultParametersOutput
+				out, err = s.core.DescribeEngineDefaultParameters(ctx, &rds.DescribeEngineDefaultParametersInput{
+					DBParameterGroupFamily: family,
+					Marker:                 marker,
+					MaxRecords:             aws.Int32(pageSize),
+				})
+				if err == nil {
+					defaults = out.EngineDefaults
+				}
+			}
+			if err != nil || defaults == nil {
+				return nil, nil, err
+			}
+			return convertParameters(defaults.Parameters), defaults.Marker, nil
+		}, fn)
+	})
+}
+
+func (s *rdsParameterGroup) Diff(ctx context.Context, other string) ([]ParameterDiff, error) {
+	from, err := s.GetParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o := newParameterGroup(s.core)
+	o.SetName(other).SetCluster(s.cluster)
+	o.describeParam.Source = s.describeParam.Source
+	o.describeClusterParam.Source = s.describeClusterParam.Source
+	to, err := o.GetParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return DiffParameters(from, to), nil
+}
+
+func (s *rdsParameterGroup) DiffDesired(ctx context.Context, desired map[string]string) ([]ParameterDiff, error) {
+	current, err := s.GetParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return DiffDesiredParameters(current, desired), nil
+}
+
+// DiffParameters returns the parameters whose value differs between from and
+// to, sorted by name.
+func DiffParameters(from, to []Parameter) []ParameterDiff {
+	return diffParameterValues(parameterValues(from), parameterValues(to), false)
+}
+
+// DiffDesiredParameters returns the parameters of desired whose value differs
+// from current, sorted by name. The parameters absent from desired are ignored.
+func DiffDesiredParameters(current []Parameter, desired map[string]string) []ParameterDiff {
+	return diffParameterValues(parameterValues(current), desired, true)
+}
+
+func diffParameterValues(from, to map[string]string, desiredOnly bool) []ParameterDiff {
+	names := map[string]bool{}
+	for name := range to {
+		names[name] = true
+	}
+	if !desiredOnly {
+		for name := range from {
+			names[name] = true
+		}
+	}
+
+	var diffs []ParameterDiff
+	for name := range names {
+		if from[name] != to[name] {
+			diffs = append(diffs, ParameterDiff{Name: name, From: from[name], To: to[name]})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+func parameterValues(params []Parameter) map[string]string {
+	values := make(map[string]string, len(params))
+	for _, p := range params {
+		values[p.Name] = p.Value
+	}
+	return values
+}
+
+// batchParameters splits params into the batches accepted by one call.
+func batchParameters(params []types.Parameter) [][]types.Parameter {
+	var batches [][]types.Parameter
+	for len(params) > maxModifyParameters {
+		batches = append(batches, params[:maxModifyParameters])
+		params = params[maxModifyParameters:]
+	}
+	if len(params) > 0 {
+		batches = append(batches, params)
+	}
+	return batches
+}
+
+func convertParameters(in []types.Parameter) []Parameter {
+	out := make([]Parameter, 0, len(in))
+	for _, p := range in {
+		out = append(out, Parameter{
+			Name:                 aws.ToString(p.ParameterName),
+			Value:                aws.ToString(p.ParameterValue),
+			ApplyMethod:          ApplyMethod(p.ApplyMethod),
+			ApplyType:            aws.ToString(p.ApplyType),
+			DataType:             aws.ToString(p.DataType),
+			AllowedValues:        aws.ToString(p.AllowedValues),
+			Description:          aws.ToString(p.Description),
+			Source:               aws.ToString(p.Source),
+			MinimumEngineVersion: aws.ToString(p.MinimumEngineVersion),
+			IsModifiable:         p.IsModifiable,
+		})
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParameterGroup", func() {
+	It("should feed the db and cluster inputs from the setters", func() {
+		b := NewService(aws.Config{Region: "us-east-1"}).ParameterGroup().
+			SetName("shop").
+			SetFamily("aurora-mysql8.0").
+			SetSourceName("default.aurora-mysql8.0").
+			SetSource(ParameterSourceUser).(*rdsParameterGroup)
+
+		Expect(aws.ToString(b.createParam.DBParameterGroupName)).To(Equal("shop"))
+		Expect(aws.ToString(b.copyClusterParam.TargetDBClusterParameterGroupIdentifier)).To(Equal("shop"))
+		Expect(aws.ToString(b.copyParam.SourceDBParameterGroupIdentifier)).To(Equal("default.aurora-mysql8.0"))
+		Expect(aws.ToString(b.describeClusterParam.Source)).To(Equal("user"))
+	})
+
+	It("should modify parameters in batches of 20", func() {
+		b := newParameterGroup(nil)
+		for i := 0; i < 45; i++ {
+			b.SetParameter(fmt.Sprintf("p%d", i), "1", ApplyMethodPendingReboot)
+		}
+		batches := batchParameters(b.parameters)
+		Expect(batches).To(HaveLen(3))
+		Expect(batches[0]).To(HaveLen(20))
+		Expect(batches[2]).To(HaveLen(5))
+		Expect(string(batches[2][0].ApplyMethod)).To(Equal("pending-reboot"))
+		Expect(batchParameters(nil)).To(BeEmpty())
+	})
+
+	It("should diff two groups and a group against desired values", func() {
+		current := []Parameter{
+			{Name: "max_connections", Value: "100"},
+			{Name: "time_zone", Value: "UTC"},
+			{Name: "binlog_format"},
+		}
+		other := []Parameter{
+			{Name: "max_connections", Value: "200"},
+			{Name: "time_zone", Value: "UTC"},
+			{Name: "slow_query_log", Value: "1"},
+		}
+
+		Expect(DiffParameters(current, other)).To(Equal([]ParameterDiff{
+			{Name: "max_connections", From: "100", To: "200"},
+			{Name: "slow_query_log", To: "1"},
+		}))
+		Expect(DiffDesiredParameters(current, map[string]string{
+			"max_connections": "100",
+			"binlog_format":   "ROW",
+		})).To(Equal([]ParameterDiff{{Name: "binlog_format", To: "ROW"}}))
+	})
+
+	It("should fail to read or diff a missing group", func() {
+		ctx := context.Background()
+		pg := newParameterGroup(fakeClient("DBParameterGroupNotFound", ""))
+		pg.SetName("typo-name")
+		_, err := pg.GetParameters(ctx)
+		Expect(err).To(MatchError(ErrNotFound))
+		_, err = pg.Diff(ctx, "custom-mysql80")
+		Expect(err).To(MatchError(ErrNotFound))
+		_, err = pg.DiffDesired(ctx, map[string]string{"binlog_format": "ROW"})
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("should diff the parameters of the source set on both groups", func() {
+		core := serveClient(func(req *http.Request) (int, string) {
+			Expect(req.ParseForm()).To(Succeed())
+			value := "100"
+			if req.PostForm.Get("DBParameterGroupName") == "custom-mysql80" {
+				value = "200"
+			}
+			params := `<Parameter><ParameterName>max_connections</ParameterName><ParameterValue>` + value +
+				`</ParameterValue><Source>user</Source></Parameter>`
+			if req.PostForm.Get("Source") != ParameterSourceUser {
+				params += `<Parameter><ParameterName>time_zone</ParameterName><ParameterValue>UTC</ParameterValue><Source>engine-default</Source></Parameter>`
+			}
+			return http.StatusOK, `<DescribeDBParametersResponse><DescribeDBParametersResult><Parameters>` + params +
+				`</Parameters></DescribeDBParametersResult></DescribeDBParametersResponse>`
+		})
+
+		pg := newParameterGroup(core)
+		pg.SetName("shop").SetSource(ParameterSourceUser)
+		diffs, err := pg.Diff(context.Background(), "custom-mysql80")
+		Expect(err).To(BeNil())
+		Expect(diffs).To(Equal([]ParameterDiff{{Name: "max_connections", From: "100", To: "200"}}))
+	})
+})
@@ -19,73 +19,90 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 )
 
+// RDS creates the builders of the rds resources. Every call returns a new
+// builder, so builders are never shared between callers. A single builder is
+// not safe for concurrent use, and operations never modify the builder they
+// are called on.
 type RDS interface {
 	Instance() Instance
 	Cluster() Cluster
 	Aurora() Aurora
+	Scheduler() Scheduler
+	Retention() Retention
+	ExportTask() ExportTask
+	ParameterGroup() ParameterGroup
+	SubnetGroup() SubnetGroup
+	OptionGroup() OptionGroup
+	Tagging() Tagging
+	GlobalCluster() GlobalCluster
+	BlueGreen() BlueGreen
+	Maintenance() Maintenance
+	EngineUpgrade() EngineUpgrade
 }
 
 type service struct {
-	instance *rdsInstance
-	cluster  *rdsCluster
-	aurora   *rdsAurora
+	core *rds.Client
 }
 
 func (s *service) Instance() Instance {
-	return s.instance
+	return newInstance(s.core)
 }
 
 func (s *service) Cluster() Cluster {
-	return s.cluster
+	return newCluster(s.core)
 }
 
 func (s *service) Aurora() Aurora {
-	return s.aurora
+	return newAurora(s.core)
 }
 
-func NewService(sess aws.Config) *service {
+func (s *service) Scheduler() Scheduler {
+	return newScheduler(s.core)
+}
+
+func (s *service) Retention() Retention {
+	return newRetention(s.core)
+}
+
+func (s *service) ExportTask() ExportTask {
+	return newExportTask(s.core)
+}
+
+func (s *service) ParameterGroup() ParameterGroup {
+	return newParameterGroup(s.core)
+}
+
+func (s *service) SubnetGroup() SubnetGroup {
+	return newSubnetGroup(s.core)
+}
+
+func (s *service) OptionGroup() OptionGroup {
+	return newOptionGroup(s.core)
+}
+
+func (s *service) Tagging() Tagging {
+	return newTagging(s.core)
+}
+
+func (s *service) GlobalCluster() GlobalCluster {
+	return newGlobalCluster(s.core)
+}
+
+func (s *service) BlueGreen() BlueGreen {
+	return newBlueGreen(s.core)
+}
+
+func (s *service) Maintenance() Maintenance {
+	return newMaintenance(s.core)
+}
+
+func (s *service) EngineUpgrade() EngineUpgrade {
+	return newEngineUpgrade(s.core)
+}
+
+// NewService returns an RDS whose builders share one goroutine-safe client.
+func NewService(sess aws.Config, optFns ...func(*rds.Options)) *service {
 	return &service{
-		instance: &rdsInstance{
-			core:                     rds.NewFromConfig(sess),
-			createInstanceParam:      &rds.CreateDBInstanceInput{},
-			deleteInstanceParam:      &rds.DeleteDBInstanceInput{},
-			rebootInstanceParam:      &rds.RebootDBInstanceInput{},
-			describeInstanceParam:    &rds.DescribeDBInstancesInput{},
-			restoreInstancePitrParam: &rds.RestoreDBInstanceToPointInTimeInput{},
-			createSnapshotParam:      &rds.CreateDBSnapshotInput{},
-			describeSnapshotParam:    &rds.DescribeDBSnapshotsInput{},
-			restoreFromSnapshotParam: &rds.RestoreDBInstanceFromDBSnapshotInput{},
-		},
-		cluster: &rdsCluster{
-			core:                              rds.NewFromConfig(sess),
-			createClusterParam:                &rds.CreateDBClusterInput{},
-			deleteClusterParam:                &rds.DeleteDBClusterInput{},
-			failoverClusterParam:              &rds.FailoverDBClusterInput{},
-			failoverGlobalClusterParam:        &rds.FailoverGlobalClusterInput{},
-			rebootClusterParam:                &rds.RebootDBClusterInput{},
-			describeClusterParam:              &rds.DescribeDBClustersInput{},
-			restoreDBClusterPitrParam:         &rds.RestoreDBClusterToPointInTimeInput{},
-			restoreDBClusterFromSnapshotParam: &rds.RestoreDBClusterFromSnapshotInput{},
-			createDBClusterSnapshotParam:      &rds.CreateDBClusterSnapshotInput{},
-			describeDBClusterSnapshotParam:    &rds.DescribeDBClusterSnapshotsInput{},
-		},
-		aurora: &rdsAurora{
-			core:                            rds.NewFromConfig(sess),
-			createClusterParam:              &rds.CreateDBClusterInput{},
-			deleteClusterParam:              &rds.DeleteDBClusterInput{},
-			failoverClusterParam:            &rds.FailoverDBClusterInput{},
-			failoverGlobalClusterParam:      &rds.FailoverGlobalClusterInput{},
-			rebootClusterParam:              &rds.RebootDBClusterInput{},
-			describeClusterParam:            &rds.DescribeDBClustersInput{},
-			restoreClusterPitrParam:         &rds.RestoreDBClusterToPointInTimeInput{},
-			createInstanceParam:             &rds.CreateDBInstanceInput{},
-			deleteInstanceParam:             &rds.DeleteDBInstanceInput{},
-			rebootInstanceParam:             &rds.RebootDBInstanceInput{},
-			describeInstanceParam:           &rds.DescribeDBInstancesInput{},
-			restoreInstancePitrParam:        &rds.RestoreDBInstanceToPointInTimeInput{},
-			createClusterSnapshotParam:      &rds.CreateDBClusterSnapshotInput{},
-			describeClusterSnapshotParam:    &rds.DescribeDBClusterSnapshotsInput{},
-			restoreClusterFromSnapshotParam: &rds.RestoreDBClusterFromSnapshotInput{},
-		},
+		core: rds.NewFromConfig(sess, optFns...),
 	}
 }
@@ -212,7 +212,7 @@ func Test_CreateRDSSubnetsGroup(t *testing.T) {
 	accessKeyId, _ := os.LookupEnv(EnvAWSAccessKey)
 	secretAccessKey, _ := os.LookupEnv(EnvAWSSecretAccessKey)
 	sess := dbmesh.NewSessions().SetCredential(region, accessKeyId, secretAccessKey).Build()
-	client := NewService(sess[region]).cluster.core
+	client := NewService(sess[region]).core
 	snginput := &rds.CreateDBSubnetGroupInput{
 		SubnetIds:                []string{"subnet-gg", "subnet-gg", "subnet-gg"},
 		DBSubnetGroupName:        aws.String("test"),
@@ -276,7 +276,7 @@ func Test_DescribeRDSAurora(t *testing.T) {
 		DBClusterIdentifier: aws.String(TestDBIdentifier),
 	}
 
-	output, err := NewService(sess[region]).cluster.core.DescribeDBClusters(context.TODO(), input)
+	output, err := NewService(sess[region]).core.DescribeDBClusters(context.TODO(), input)
 
 	if err != nil {
 		t.Fatalf("%+v\n", err)
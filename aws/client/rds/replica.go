@@ -0,0 +1,161 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ReplicaNode is an instance of a replication tree with its read replicas.
+type ReplicaNode struct {
+	// Identifier is the instance identifier, or the ARN for a replica in another region.
+	Identifier string
+	Region     string
+	// CrossRegion is set when the replica lives in another region than the tree root.
+	CrossRegion bool
+	// Instance is nil for replicas in another region, which are not described,
+	// and for replicas deleted while describing the tree.
+	Instance *DescInstance
+	Replicas []*ReplicaNode
+}
+
+// Replicating reports whether the read replication of the replica is normal.
+// It is false while the replication is lagging, stopped or broken.
+func (n *ReplicaNode) Replicating() bool {
+	if n.Instance == nil {
+		return false
+	}
+	for _, info := range n.Instance.ReadReplicaStatusInfos {
+		if info.StatusType == "read replication" {
+			return info.Normal && info.Status == "replicating"
+		}
+	}
+	return false
+}
+
+// Flatten returns the node followed by all its replicas, depth first.
+func (n *ReplicaNode) Flatten() []*ReplicaNode {
+	nodes := []*ReplicaNode{n}
+	for _, r := range n.Replicas {
+		nodes = append(nodes, r.Flatten()...)
+	}
+	return nodes
+}
+
+// SetKmsKeyId sets the key encrypting a cross-region read replica or a
+// snapshot copy, in the destination region.
+func (s *rdsInstance) SetKmsKeyId(id string) Instance {
+	s.createReadReplicaParam.KmsKeyId = aws.String(id)
+	s.copySnapshotParam.KmsKeyId = aws.String(id)
+	return s
+}
+
+// SetSourceRegion sets the region of the source instance of a cross-region
+// read replica, or of the source snapshot of a cross-region copy. The
+// presigned url is generated from it by the sdk, and the source must then be
+// set by ARN with SetSourceDBInstanceIdentifier or SetSnapshotIdentifier.
+func (s *rdsInstance) SetSourceRegion(region string) Instance {
+	s.createReadReplicaParam.SourceRegion = aws.String(region)
+	s.copySnapshotParam.SourceRegion = aws.String(region)
+	return s
+}
+
+// SetPreferredBackupWindow sets the daily backup window of a promoted read replica, formatted hh24:mi-hh24:mi.
+func (s *rdsInstance) SetPreferredBackupWindow(window string) Instance {
+	s.promoteReadReplicaParam.PreferredBackupWindow = aws.String(window)
+	return s
+}
+
+// CreateReadReplica creates the instance set by SetDBInstanceIdentifier as a
+// read replica of the instance set by SetSourceDBInstanceIdentifier.
+func (s *rdsInstance) CreateReadReplica(ctx context.Context) error {
+	_, err := s.core.CreateDBInstanceReadReplica(ctx, s.createReadReplicaParam)
+	return wrapError(err)
+}
+
+// PromoteReadReplica detaches the read replica from its source and makes it
+// a standalone instance, with the retention set by SetBackupRetentionPeriod.
+func (s *rdsInstance) PromoteReadReplica(ctx context.Context) error {
+	_, err := s.core.PromoteReadReplica(ctx, s.promoteReadReplicaParam)
+	return wrapError(err)
+}
+
+// DescribeReplicaTree describes the instance and, recursively, its read
+// replicas. Replicas in another region are listed but not described. It
+// returns nil when the instance does not exist.
+func (s *rdsInstance) DescribeReplicaTree(ctx context.Context) (*ReplicaNode, error) {
+	id := s.describeInstanceParam.DBInstanceIdentifier
+	if id == nil {
+		return nil, errors.New("db instance identifier is required")
+	}
+	root, err := s.describeReplica(ctx, *id, "", map[string]bool{})
+	if err != nil || root.Instance == nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// describeReplica describes id and its replicas, region is the region of the
+// tree root and is taken from the ARN of the root when empty.
+func (s *rdsInstance) describeReplica(ctx context.Context, id, region string, seen map[string]bool) (*ReplicaNode, error) {
+	node := &ReplicaNode{Identifier: id, Region: region}
+	if r := arnRegion(id); r != "" && region != "" && r != region {
+		node.Region = r
+		node.CrossRegion = true
+		return node, nil
+	}
+	if seen[id] {
+		return node, nil
+	}
+	seen[id] = true
+
+	desc, err := newInstance(s.core).SetDBInstanceIdentifier(id).Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil || desc.DBInstanceIdentifier == "" {
+		return node, nil
+	}
+	node.Instance = desc
+	if region == "" {
+		region = arnRegion(desc.DBInstanceArn)
+		node.Region = region
+	}
+	for _, replica := range desc.ReadReplicaDBInstanceIdentifiers {
+		child, err := s.describeReplica(ctx, replica, region, seen)
+		if err != nil {
+			return nil, err
+		}
+		node.Replicas = append(node.Replicas, child)
+	}
+	return node, nil
+}
+
+// arnRegion returns the region of an ARN such as arn:aws:rds:us-east-1:123456789012:db:foo,
+// or "" when id is not an ARN.
+func arnRegion(id string) string {
+	if !strings.HasPrefix(id, "arn:") {
+		return ""
+	}
+	parts := strings.SplitN(id, ":", 5)
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[3]
+}
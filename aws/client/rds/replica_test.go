@@ -0,0 +1,65 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replica", func() {
+	It("should feed the read replica inputs from the setters", func() {
+		b := NewService(aws.Config{Region: "us-west-2"}).Instance().
+			SetDBInstanceIdentifier("replica").
+			SetSourceDBInstanceIdentifier("arn:aws:rds:us-east-1:123456789012:db:primary").
+			SetSourceRegion("us-east-1").
+			SetKmsKeyId("alias/rds").
+			SetBackupRetentionPeriod(7).(*rdsInstance)
+
+		Expect(aws.ToString(b.createReadReplicaParam.DBInstanceIdentifier)).To(Equal("replica"))
+		Expect(aws.ToString(b.createReadReplicaParam.SourceDBInstanceIdentifier)).To(HavePrefix("arn:"))
+		Expect(aws.ToString(b.createReadReplicaParam.SourceRegion)).To(Equal("us-east-1"))
+		Expect(aws.ToString(b.createReadReplicaParam.KmsKeyId)).To(Equal("alias/rds"))
+		Expect(aws.ToString(b.promoteReadReplicaParam.DBInstanceIdentifier)).To(Equal("replica"))
+		Expect(aws.ToInt32(b.promoteReadReplicaParam.BackupRetentionPeriod)).To(Equal(int32(7)))
+	})
+
+	It("should report the replication state and flatten the tree", func() {
+		lagging := &ReplicaNode{Identifier: "b", Instance: &DescInstance{
+			ReadReplicaStatusInfos: []ReadReplicaStatus{{StatusType: "read replication", Status: "error", Normal: false}},
+		}}
+		healthy := &ReplicaNode{Identifier: "a", Instance: &DescInstance{
+			ReadReplicaStatusInfos: []ReadReplicaStatus{{StatusType: "read replication", Status: "replicating", Normal: true}},
+		}, Replicas: []*ReplicaNode{lagging}}
+		remote := &ReplicaNode{Identifier: "arn:aws:rds:eu-west-1:123456789012:db:c", CrossRegion: true}
+		root := &ReplicaNode{Identifier: "primary", Instance: &DescInstance{}, Replicas: []*ReplicaNode{healthy, remote}}
+
+		Expect(healthy.Replicating()).To(BeTrue())
+		Expect(lagging.Replicating()).To(BeFalse())
+		Expect(remote.Replicating()).To(BeFalse())
+
+		var ids []string
+		for _, n := range root.Flatten() {
+			ids = append(ids, n.Identifier)
+		}
+		Expect(ids).To(Equal([]string{"primary", "a", "b", remote.Identifier}))
+	})
+
+	It("should parse the region of an ARN", func() {
+		Expect(arnRegion("arn:aws:rds:eu-west-1:123456789012:db:foo")).To(Equal("eu-west-1"))
+		Expect(arnRegion("foo")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,355 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+const (
+	DefaultDeleteInterval = time.Second
+
+	snapshotTypeManual = "manual"
+)
+
+// RetentionPolicy decides which manual snapshots of every instance or cluster
+// are kept. A snapshot is kept when any rule keeps it.
+type RetentionPolicy struct {
+	// KeepLast keeps the newest snapshots.
+	KeepLast int
+	// KeepDaily, KeepWeekly and KeepMonthly keep the newest snapshot of each
+	// of the latest days, ISO weeks and months having snapshots.
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
+	// MinAge keeps the snapshots younger than it.
+	MinAge time.Duration
+	// ExcludeTags keeps the snapshots carrying one of the tags, with any
+	// value when the value is empty.
+	ExcludeTags map[string]string
+	// Location is the time zone of the days, weeks and months, UTC when nil.
+	Location *time.Location
+}
+
+// Validate rejects the policies which would delete every snapshot.
+func (p *RetentionPolicy) Validate() error {
+	if p.KeepLast+p.KeepDaily+p.KeepWeekly+p.KeepMonthly <= 0 && p.MinAge <= 0 {
+		return errors.New("retention policy keeps no snapshot")
+	}
+	if p.KeepLast < 0 || p.KeepDaily < 0 || p.KeepWeekly < 0 || p.KeepMonthly < 0 || p.MinAge < 0 {
+		return errors.New("retention policy values must not be negative")
+	}
+	return nil
+}
+
+// RetentionSnapshot is a DB or DB cluster snapshot evaluated by a policy.
+type RetentionSnapshot struct {
+	Identifier string
+	// Source is the instance or cluster of the snapshot.
+	Source     string
+	Cluster    bool
+	Type       string
+	Status     string
+	CreateTime time.Time
+	Tags       map[string]string
+}
+
+func RetentionSnapshotsFromInstance(descs []*DescSnapshot) []RetentionSnapshot {
+	var out []RetentionSnapshot
+	for _, d := range descs {
+		out = append(out, RetentionSnapshot{
+			Identifier: d.DBSnapshotIdentifier,
+			Source:     d.DBInstanceIdentifier,
+			Type:       d.SnapshotType,
+			Status:     d.Status,
+			CreateTime: d.SnapshotCreateTime,
+			Tags:       d.Tags,
+		})
+	}
+	return out
+}
+
+func RetentionSnapshotsFromCluster(descs []*DescClusterSnapshot) []RetentionSnapshot {
+	var out []RetentionSnapshot
+	for _, d := range descs {
+		out = append(out, RetentionSnapshot{
+			Identifier: d.DBClusterSnapshotIdentifier,
+			Source:     d.DBClusterIdentifier,
+			Cluster:    true,
+			Type:       d.SnapshotType,
+			Status:     d.Status,
+			CreateTime: d.SnapshotCreateTime,
+			Tags:       d.Tags,
+		})
+	}
+	return out
+}
+
+// RetentionDecision is the fate of a snapshot. Reasons lists the rules
+// keeping it and is empty for deleted snapshots.
+type RetentionDecision struct {
+	Snapshot RetentionSnapshot
+	Reasons  []string
+	// Err is the error of the deletion.
+	Err error
+}
+
+type RetentionPlan struct {
+	Keep   []RetentionDecision
+	Delete []RetentionDecision
+}
+
+// String renders the plan one snapshot per line, as printed by a dry run.
+func (p *RetentionPlan) String() string {
+	var b strings.Builder
+	for _, d := range p.Keep {
+		fmt.Fprintf(&b, "keep   %s %s (%s)\n", d.Snapshot.Identifier, d.Snapshot.CreateTime.Format(time.RFC3339), strings.Join(d.Reasons, ", "))
+	}
+	for _, d := range p.Delete {
+		fmt.Fprintf(&b, "delete %s %s", d.Snapshot.Identifier, d.Snapshot.CreateTime.Format(time.RFC3339))
+		if d.Err != nil {
+			fmt.Fprintf(&b, " failed: %s", d.Err)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Plan splits snapshots into kept and deleted ones as of now. The rules apply
+// to the manual and available snapshots of each source separately, the others
+// are always kept.
+func (p *RetentionPolicy) Plan(snapshots []RetentionSnapshot, now time.Time) *RetentionPlan {
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	sources := map[string][]RetentionSnapshot{}
+	var order []string
+	for _, s := range snapshots {
+		key := fmt.Sprintf("%t/%s", s.Cluster, s.Source)
+		if _, ok := sources[key]; !ok {
+			order = append(order, key)
+		}
+		sources[key] = append(sources[key], s)
+	}
+
+	plan := &RetentionPlan{}
+	for _, key := range order {
+		group := sources[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].CreateTime.After(group[j].CreateTime)
+		})
+
+		var (
+			eligible int
+			days     = newRetentionBucket(p.KeepDaily, "daily")
+			weeks    = newRetentionBucket(p.KeepWeekly, "weekly")
+			months   = newRetentionBucket(p.KeepMonthly, "monthly")
+		)
+		for _, s := range group {
+			d := RetentionDecision{Snapshot: s}
+			tag := p.excluded(s.Tags)
+			switch {
+			case s.Type != snapshotTypeManual:
+				d.Reasons = append(d.Reasons, "not manual")
+			case s.Status != SnapshotStatusAvailable:
+				d.Reasons = append(d.Reasons, "status "+s.Status)
+			case tag != "":
+				d.Reasons = append(d.Reasons, "excluded by tag "+tag)
+			default:
+				eligible++
+				if eligible <= p.KeepLast {
+					d.Reasons = append(d.Reasons, fmt.Sprintf("last %d", p.KeepLast))
+				}
+				t := s.CreateTime.In(loc)
+				year, week := t.ISOWeek()
+				d.Reasons = days.keep(t.Format("2006-01-02"), d.Reasons)
+				d.Reasons = weeks.keep(fmt.Sprintf("%d-W%02d", year, week), d.Reasons)
+				d.Reasons = months.keep(t.Format("2006-01"), d.Reasons)
+				if p.MinAge > 0 && now.Sub(s.CreateTime) < p.MinAge {
+					d.Reasons = append(d.Reasons, "younger than "+p.MinAge.String())
+				}
+			}
+
+			if len(d.Reasons) > 0 {
+				plan.Keep = append(plan.Keep, d)
+			} else {
+				plan.Delete = append(plan.Delete, d)
+			}
+		}
+	}
+	return plan
+}
+
+func (p *RetentionPolicy) excluded(tags map[string]string) string {
+	for k, v := range p.ExcludeTags {
+		if matchTag(tags, k, v) {
+			return k
+		}
+	}
+	return ""
+}
+
+// retentionBucket keeps the newest snapshot of the first size periods.
+type retentionBucket struct {
+	size   int
+	reason string
+	seen   map[string]bool
+}
+
+func newRetentionBucket(size int, reason string) *retentionBucket {
+	return &retentionBucket{size: size, reason: reason, seen: map[string]bool{}}
+}
+
+func (b *retentionBucket) keep(period string, reasons []string) []string {
+	if b.seen[period] || len(b.seen) >= b.size {
+		return reasons
+	}
+	b.seen[period] = true
+	return append(reasons, b.reason)
+}
+
+// Retention applies a RetentionPolicy to the manual snapshots of the account,
+// or of the instance and cluster set by SetDBInstanceIdentifier and
+// SetDBClusterIdentifier.
+type Retention interface {
+	SetPolicy(policy RetentionPolicy) Retention
+	SetDBInstanceIdentifier(id string) Retention
+	SetDBClusterIdentifier(id string) Retention
+	SetDryRun(enable bool) Retention
+	SetDeleteInterval(interval time.Duration) Retention
+
+	Plan(ctx context.Context, now time.Time) (*RetentionPlan, error)
+	// Apply deletes the snapshots planned for deletion one at a time, every
+	// delete interval, unless in dry run. A failed deletion is reported in
+	// its RetentionDecision and does not stop Apply.
+	Apply(ctx context.Context, now time.Time) (*RetentionPlan, error)
+}
+
+type rdsRetention struct {
+	core *rds.Client
+
+	policy         RetentionPolicy
+	instanceId     string
+	clusterId      string
+	dryRun         bool
+	deleteInterval time.Duration
+}
+
+func newRetention(core *rds.Client) *rdsRetention {
+	return &rdsRetention{core: core, deleteInterval: DefaultDeleteInterval}
+}
+
+func (s *rdsRetention) SetPolicy(policy RetentionPolicy) Retention {
+	s.policy = policy
+	return s
+}
+
+// SetDBInstanceIdentifier restricts the retention to the snapshots of the instance.
+func (s *rdsRetention) SetDBInstanceIdentifier(id string) Retention {
+	s.instanceId = id
+	return s
+}
+
+// SetDBClusterIdentifier restricts the retention to the snapshots of the cluster.
+func (s *rdsRetention) SetDBClusterIdentifier(id string) Retention {
+	s.clusterId = id
+	return s
+}
+
+// SetDryRun makes Apply return the plan without deleting anything.
+func (s *rdsRetention) SetDryRun(enable bool) Retention {
+	s.dryRun = enable
+	return s
+}
+
+// SetDeleteInterval sets the delay between two deletions, DefaultDeleteInterval by default.
+func (s *rdsRetention) SetDeleteInterval(interval time.Duration) Retention {
+	s.deleteInterval = interval
+	return s
+}
+
+func (s *rdsRetention) Plan(ctx context.Context, now time.Time) (*RetentionPlan, error) {
+	if err := s.policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	var snapshots []RetentionSnapshot
+	if s.instanceId != "" || s.clusterId == "" {
+		param := &rds.DescribeDBSnapshotsInput{SnapshotType: aws.String(snapshotTypeManual)}
+		if s.instanceId != "" {
+			param.DBInstanceIdentifier = aws.String(s.instanceId)
+		}
+		descs, err := collect(func(fn func(*DescSnapshot) bool) error {
+			return iterateSnapshots(ctx, s.core, param, 0, fn)
+		})
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, RetentionSnapshotsFromInstance(descs)...)
+	}
+	if s.clusterId != "" || s.instanceId == "" {
+		param := &rds.DescribeDBClusterSnapshotsInput{SnapshotType: aws.String(snapshotTypeManual)}
+		if s.clusterId != "" {
+			param.DBClusterIdentifier = aws.String(s.clusterId)
+		}
+		descs, err := collect(func(fn func(*DescClusterSnapshot) bool) error {
+			return iterateClusterSnapshots(ctx, s.core, param, 0, fn)
+		})
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, RetentionSnapshotsFromCluster(descs)...)
+	}
+
+	return s.policy.Plan(snapshots, now), nil
+}
+
+func (s *rdsRetention) Apply(ctx context.Context, now time.Time) (*RetentionPlan, error) {
+	plan, err := s.Plan(ctx, now)
+	if err != nil || s.dryRun {
+		return plan, err
+	}
+
+	for i := range plan.Delete {
+		if i > 0 && s.deleteInterval > 0 {
+			timer := time.NewTimer(s.deleteInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return plan, ctx.Err()
+			case <-timer.C:
+			}
+		}
+		plan.Delete[i].Err = s.delete(ctx, plan.Delete[i].Snapshot)
+	}
+	return plan, nil
+}
+
+func (s *rdsRetention) delete(ctx context.Context, snapshot RetentionSnapshot) error {
+	if snapshot.Cluster {
+		return newCluster(s.core).SetSnapshotIdentifier(snapshot.Identifier).DeleteSnapshot(ctx)
+	}
+	return newInstance(s.core).SetSnapshotIdentifier(snapshot.Identifier).DeleteSnapshot(ctx)
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retention", func() {
+	now := time.Date(2023, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	// daily returns a manual snapshot of foo taken every day at noon for n days.
+	daily := func(n int) []RetentionSnapshot {
+		var out []RetentionSnapshot
+		for i := 0; i < n; i++ {
+			t := now.AddDate(0, 0, -i)
+			out = append(out, RetentionSnapshot{
+				Identifier: fmt.Sprintf("foo-%s", t.Format("2006-01-02")),
+				Source:     "foo",
+				Type:       "manual",
+				Status:     SnapshotStatusAvailable,
+				CreateTime: t,
+			})
+		}
+		return out
+	}
+	kept := func(plan *RetentionPlan) []string {
+		var ids []string
+		for _, d := range plan.Keep {
+			ids = append(ids, d.Snapshot.Identifier)
+		}
+		return ids
+	}
+
+	It("should reject policies keeping nothing", func() {
+		Expect((&RetentionPolicy{}).Validate()).ToNot(Succeed())
+		Expect((&RetentionPolicy{KeepLast: -1, KeepDaily: 3}).Validate()).ToNot(Succeed())
+		Expect((&RetentionPolicy{KeepLast: 1}).Validate()).To(Succeed())
+	})
+
+	It("should keep the last snapshots and the newest of every period", func() {
+		plan := (&RetentionPolicy{KeepLast: 2, KeepWeekly: 2, KeepMonthly: 3}).Plan(daily(90), now)
+
+		Expect(kept(plan)).To(Equal([]string{
+			"foo-2023-03-31", // last, weekly 2023-W13 and monthly 2023-03
+			"foo-2023-03-30", // last
+			"foo-2023-03-26", // weekly 2023-W12
+			"foo-2023-02-28", // monthly 2023-02
+			"foo-2023-01-31", // monthly 2023-01
+		}))
+		Expect(plan.Keep[0].Reasons).To(Equal([]string{"last 2", "weekly", "monthly"}))
+		Expect(plan.Delete).To(HaveLen(85))
+	})
+
+	It("should keep young, excluded, automated and unavailable snapshots", func() {
+		snapshots := daily(10)
+		snapshots[5].Tags = map[string]string{"legal-hold": "2023"}
+		snapshots[6].Type = "automated"
+		snapshots[7].Status = SnapshotStatusCopying
+
+		plan := (&RetentionPolicy{MinAge: 72 * time.Hour, ExcludeTags: map[string]string{"legal-hold": ""}}).Plan(snapshots, now)
+		Expect(kept(plan)).To(Equal([]string{
+			"foo-2023-03-31", "foo-2023-03-30", "foo-2023-03-29",
+			"foo-2023-03-26", "foo-2023-03-25", "foo-2023-03-24",
+		}))
+		Expect(plan.Keep[3].Reasons).To(Equal([]string{"excluded by tag legal-hold"}))
+		Expect(plan.String()).To(ContainSubstring("delete foo-2023-03-22"))
+	})
+
+	It("should apply the policy to every source separately", func() {
+		snapshots := daily(3)
+		for _, s := range daily(3) {
+			s.Identifier, s.Source, s.Cluster = "bar"+s.Identifier[3:], "bar", true
+			snapshots = append(snapshots, s)
+		}
+		plan := (&RetentionPolicy{KeepLast: 1}).Plan(snapshots, now)
+		Expect(kept(plan)).To(Equal([]string{"foo-2023-03-31", "bar-2023-03-31"}))
+		Expect(plan.Delete).To(HaveLen(4))
+	})
+})
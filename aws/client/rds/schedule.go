@@ -0,0 +1,232 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+const week = 7 * 24 * time.Hour
+
+// WeeklySchedule is a weekly calendar of the windows in which databases run.
+// Databases are stopped outside of the windows.
+type WeeklySchedule struct {
+	location *time.Location
+	windows  []weeklyWindow
+}
+
+// weeklyWindow is [start, stop) as offsets from Sunday 00:00.
+type weeklyWindow struct {
+	start time.Duration
+	stop  time.Duration
+}
+
+// NewWeeklySchedule returns an empty schedule in loc, UTC when loc is nil.
+func NewWeeklySchedule(loc *time.Location) *WeeklySchedule {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &WeeklySchedule{location: loc}
+}
+
+// AddWindow runs databases on day from start to stop, both offsets from
+// midnight. A stop before start ends the window on the next day.
+func (w *WeeklySchedule) AddWindow(day time.Weekday, start, stop time.Duration) *WeeklySchedule {
+	if stop <= start {
+		stop += 24 * time.Hour
+	}
+	begin := time.Duration(day)*24*time.Hour + start
+	w.windows = append(w.windows, weeklyWindow{start: begin, stop: begin + stop - start})
+	return w
+}
+
+// AddWeekdays adds the same window from Monday to Friday.
+func (w *WeeklySchedule) AddWeekdays(start, stop time.Duration) *WeeklySchedule {
+	for day := time.Monday; day <= time.Friday; day++ {
+		w.AddWindow(day, start, stop)
+	}
+	return w
+}
+
+// Running reports whether t is inside one of the windows.
+func (w *WeeklySchedule) Running(t time.Time) bool {
+	t = t.In(w.location)
+	offset := time.Duration(t.Weekday())*24*time.Hour +
+		time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	for _, win := range w.windows {
+		// windows ending next week wrap around Sunday 00:00
+		if (offset >= win.start && offset < win.stop) || offset+week < win.stop {
+			return true
+		}
+	}
+	return false
+}
+
+type ScheduleActionType string
+
+const (
+	ScheduleActionStart ScheduleActionType = "start"
+	ScheduleActionStop  ScheduleActionType = "stop"
+)
+
+// ScheduleAction is a start or stop applied, or planned in dry run, by Scheduler.
+type ScheduleAction struct {
+	Identifier string
+	Cluster    bool
+	Status     string
+	Action     ScheduleActionType
+	// Err is the error of the start or stop call.
+	Err error
+}
+
+// Scheduler starts and stops the instances and clusters carrying a tag
+// according to a WeeklySchedule. Instances which are members of a cluster
+// follow their cluster.
+type Scheduler interface {
+	SetTag(key, value string) Scheduler
+	SetSchedule(schedule *WeeklySchedule) Scheduler
+	SetDryRun(enable bool) Scheduler
+
+	// Apply starts the stopped databases inside a window and stops the
+	// available ones outside of it, as of now. Databases in any other status
+	// are left alone until the next Apply. A failed start or stop is reported
+	// in its ScheduleAction and does not stop Apply.
+	Apply(ctx context.Context, now time.Time) ([]ScheduleAction, error)
+}
+
+type rdsScheduler struct {
+	core *rds.Client
+
+	tagKey   string
+	tagValue string
+	schedule *WeeklySchedule
+	dryRun   bool
+}
+
+func newScheduler(core *rds.Client) *rdsScheduler {
+	return &rdsScheduler{core: core}
+}
+
+func (s *rdsScheduler) SetTag(key, value string) Scheduler {
+	s.tagKey = key
+	s.tagValue = value
+	return s
+}
+
+func (s *rdsScheduler) SetSchedule(schedule *WeeklySchedule) Scheduler {
+	s.schedule = schedule
+	return s
+}
+
+// SetDryRun makes Apply return the actions without starting or stopping anything.
+func (s *rdsScheduler) SetDryRun(enable bool) Scheduler {
+	s.dryRun = enable
+	return s
+}
+
+func (s *rdsScheduler) Apply(ctx context.Context, now time.Time) ([]ScheduleAction, error) {
+	if s.tagKey == "" {
+		return nil, errors.New("schedule tag key is required")
+	}
+	if s.schedule == nil {
+		return nil, errors.New("schedule is required")
+	}
+	running := s.schedule.Running(now)
+
+	var actions []ScheduleAction
+
+	clusters := rds.NewDescribeDBClustersPaginator(s.core, &rds.DescribeDBClustersInput{})
+	for clusters.HasMorePages() {
+		out, err := clusters.NextPage(ctx)
+		if err != nil {
+			return actions, wrapError(err)
+		}
+		for _, c := range out.DBClusters {
+			if !matchTag(convertTags(c.TagList), s.tagKey, s.tagValue) {
+				continue
+			}
+			status := aws.ToString(c.Status)
+			action, ok := scheduleAction(running, status)
+			if !ok {
+				continue
+			}
+			a := ScheduleAction{Identifier: aws.ToString(c.DBClusterIdentifier), Cluster: true, Status: status, Action: action}
+			if !s.dryRun {
+				a.Err = s.applyCluster(ctx, a)
+			}
+			actions = append(actions, a)
+		}
+	}
+
+	instances := rds.NewDescribeDBInstancesPaginator(s.core, &rds.DescribeDBInstancesInput{})
+	for instances.HasMorePages() {
+		out, err := instances.NextPage(ctx)
+		if err != nil {
+			return actions, wrapError(err)
+		}
+		for _, ins := range out.DBInstances {
+			if ins.DBClusterIdentifier != nil || !matchTag(convertTags(ins.TagList), s.tagKey, s.tagValue) {
+				continue
+			}
+			status := aws.ToString(ins.DBInstanceStatus)
+			action, ok := scheduleAction(running, status)
+			if !ok {
+				continue
+			}
+			a := ScheduleAction{Identifier: aws.ToString(ins.DBInstanceIdentifier), Status: status, Action: action}
+			if !s.dryRun {
+				a.Err = s.applyInstance(ctx, a)
+			}
+			actions = append(actions, a)
+		}
+	}
+
+	return actions, nil
+}
+
+func (s *rdsScheduler) applyCluster(ctx context.Context, a ScheduleAction) error {
+	cluster := newCluster(s.core).SetDBClusterIdentifier(a.Identifier)
+	if a.Action == ScheduleActionStart {
+		return cluster.Start(ctx)
+	}
+	return cluster.Stop(ctx)
+}
+
+func (s *rdsScheduler) applyInstance(ctx context.Context, a ScheduleAction) error {
+	instance := newInstance(s.core).SetDBInstanceIdentifier(a.Identifier)
+	if a.Action == ScheduleActionStart {
+		return instance.Start(ctx)
+	}
+	return instance.Stop(ctx)
+}
+
+// scheduleAction returns the action moving a database in status to the
+// running state of the schedule, if any.
+func scheduleAction(running bool, status string) (ScheduleActionType, bool) {
+	switch {
+	case running && status == string(DBInstanceStatusStopped):
+		return ScheduleActionStart, true
+	case !running && status == string(DBInstanceStatusAvailable):
+		return ScheduleActionStop, true
+	}
+	return "", false
+}
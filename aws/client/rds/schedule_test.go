@@ -0,0 +1,69 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	// 2023-01-02 is a Monday
+	at := func(day int, hour int) time.Time {
+		return time.Date(2023, 1, 1+day, hour, 0, 0, 0, time.UTC)
+	}
+
+	It("should run databases inside the weekday windows only", func() {
+		w := NewWeeklySchedule(nil).AddWeekdays(8*time.Hour, 20*time.Hour)
+		Expect(w.Running(at(1, 8))).To(BeTrue())
+		Expect(w.Running(at(5, 19))).To(BeTrue())
+		Expect(w.Running(at(1, 20))).To(BeFalse())
+		Expect(w.Running(at(1, 7))).To(BeFalse())
+		Expect(w.Running(at(6, 12))).To(BeFalse())
+		Expect(w.Running(at(0, 12))).To(BeFalse())
+	})
+
+	It("should run windows across midnight and across the end of the week", func() {
+		w := NewWeeklySchedule(nil).AddWindow(time.Saturday, 22*time.Hour, 2*time.Hour)
+		Expect(w.Running(at(6, 23))).To(BeTrue())
+		Expect(w.Running(at(7, 1))).To(BeTrue())
+		Expect(w.Running(at(7, 2))).To(BeFalse())
+	})
+
+	It("should only act on stopped or available databases", func() {
+		action, ok := scheduleAction(true, "stopped")
+		Expect(ok).To(BeTrue())
+		Expect(action).To(Equal(ScheduleActionStart))
+		action, ok = scheduleAction(false, "available")
+		Expect(ok).To(BeTrue())
+		Expect(action).To(Equal(ScheduleActionStop))
+		_, ok = scheduleAction(false, "stopping")
+		Expect(ok).To(BeFalse())
+		_, ok = scheduleAction(true, "available")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should match tags by key and optional value", func() {
+		tags := convertTags([]types.Tag{{Key: aws.String("schedule"), Value: aws.String("office-hours")}})
+		Expect(matchTag(tags, "schedule", "")).To(BeTrue())
+		Expect(matchTag(tags, "schedule", "office-hours")).To(BeTrue())
+		Expect(matchTag(tags, "schedule", "always")).To(BeFalse())
+		Expect(matchTag(tags, "owner", "")).To(BeFalse())
+	})
+})
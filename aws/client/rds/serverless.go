@@ -0,0 +1,192 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	// DBInstanceClassServerless is the instance class of the Serverless v2
+	// instances of an Aurora cluster, see SetDBInstanceClass of Aurora.
+	DBInstanceClassServerless = "db.serverless"
+
+	// ServerlessV2MinCapacity and ServerlessV2MaxCapacity bound the Aurora
+	// capacity units of a Serverless v2 cluster, set by steps of 0.5.
+	ServerlessV2MinCapacity = 0.5
+	ServerlessV2MaxCapacity = 128
+
+	engineModeServerless = "serverless"
+)
+
+// ErrServerlessUnsupported is matched by the validation error of a Serverless
+// v2 cluster whose engine, version or instance class does not support it.
+var ErrServerlessUnsupported = errors.New("serverless v2 is not supported")
+
+// ServerlessV2Scaling is the capacity range of the Serverless v2 instances of
+// a cluster, in Aurora capacity units.
+type ServerlessV2Scaling struct {
+	MinCapacity float64
+	MaxCapacity float64
+}
+
+// serverlessV2MinVersions are the first versions of each major version
+// supporting Serverless v2. Later majors support it from their first release.
+var serverlessV2MinVersions = map[string]map[string]string{
+	"aurora-mysql":      {"3": "3.02.0"},
+	"aurora-postgresql": {"13": "13.6", "14": "14.3", "15": "15.2"},
+}
+
+var serverlessV2FirstMajor = map[string]int{
+	"aurora-mysql":      3,
+	"aurora-postgresql": 13,
+}
+
+// ValidateServerlessV2 checks that engine at version supports Serverless v2
+// with the capacity range from min to max. An empty version is the default
+// version of the engine and is not checked.
+func ValidateServerlessV2(engine, version string, min, max float64) error {
+	if err := validateServerlessV2Capacity(min, max); err != nil {
+		return err
+	}
+	first, ok := serverlessV2FirstMajor[engine]
+	if !ok {
+		return fmt.Errorf("engine %q: %w", engine, ErrServerlessUnsupported)
+	}
+	if version == "" {
+		return nil
+	}
+
+	v := serverlessVersion(engine, version)
+	major, err := strconv.Atoi(strings.SplitN(v, ".", 2)[0])
+	if err != nil {
+		return fmt.Errorf("invalid %s version %q", engine, version)
+	}
+	if minVersion, ok := serverlessV2MinVersions[engine][strconv.Itoa(major)]; major < first || ok && compareVersions(v, minVersion) < 0 {
+		return fmt.Errorf("%s version %s: %w", engine, version, ErrServerlessUnsupported)
+	}
+	return nil
+}
+
+func validateServerlessV2Capacity(min, max float64) error {
+	if min < ServerlessV2MinCapacity || max > ServerlessV2MaxCapacity || min > max {
+		return fmt.Errorf("serverless v2 capacity must be within %v and %v ACU with min <= max, got %v-%v", ServerlessV2MinCapacity, ServerlessV2MaxCapacity, min, max)
+	}
+	if math.Mod(min*2, 1) != 0 || math.Mod(max*2, 1) != 0 {
+		return fmt.Errorf("serverless v2 capacity must be a multiple of 0.5 ACU, got %v-%v", min, max)
+	}
+	return nil
+}
+
+// serverlessVersion returns the Aurora version of version, such as 3.02.0
+// for 8.0.mysql_aurora.3.02.0.
+func serverlessVersion(engine, version string) string {
+	if engine == "aurora-mysql" {
+		if i := strings.Index(version, "mysql_aurora."); i >= 0 {
+			return version[i+len("mysql_aurora."):]
+		}
+	}
+	return version
+}
+
+// compareVersions compares dotted numeric versions, missing parts being 0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func newServerlessV2Scaling(min, max float64) *types.ServerlessV2ScalingConfiguration {
+	return &types.ServerlessV2ScalingConfiguration{
+		MinCapacity: aws.Float64(min),
+		MaxCapacity: aws.Float64(max),
+	}
+}
+
+// validateServerlessV2Create validates the cluster created with scaling,
+// whose instances are created with class.
+func validateServerlessV2Create(engine, version, mode *string, scaling *types.ServerlessV2ScalingConfiguration, class *string) error {
+	if scaling == nil {
+		if aws.ToString(class) == DBInstanceClassServerless {
+			return fmt.Errorf("%s instances require the serverless v2 scaling of the cluster: %w", DBInstanceClassServerless, ErrServerlessUnsupported)
+		}
+		return nil
+	}
+	if aws.ToString(mode) == engineModeServerless {
+		return fmt.Errorf("engine mode serverless is serverless v1: %w", ErrServerlessUnsupported)
+	}
+	return ValidateServerlessV2(aws.ToString(engine), aws.ToString(version), aws.ToFloat64(scaling.MinCapacity), aws.ToFloat64(scaling.MaxCapacity))
+}
+
+func validateServerlessV2Modify(scaling *types.ServerlessV2ScalingConfiguration) error {
+	if scaling == nil {
+		return nil
+	}
+	return validateServerlessV2Capacity(aws.ToFloat64(scaling.MinCapacity), aws.ToFloat64(scaling.MaxCapacity))
+}
+
+// SetServerlessV2Scaling sets the capacity range of the Serverless v2
+// instances of the cluster, in Aurora capacity units.
+func (s *rdsCluster) SetServerlessV2Scaling(min, max float64) Cluster {
+	scaling := newServerlessV2Scaling(min, max)
+	s.createClusterParam.ServerlessV2ScalingConfiguration = scaling
+	s.restoreDBClusterPitrParam.ServerlessV2ScalingConfiguration = scaling
+	s.restoreDBClusterFromSnapshotParam.ServerlessV2ScalingConfiguration = scaling
+	s.modifyClusterParam.ServerlessV2ScalingConfiguration = scaling
+	return s
+}
+
+// SetServerlessV2Scaling sets the capacity range of the Serverless v2
+// instances of the cluster, in Aurora capacity units. The instances are
+// Serverless v2 when created with SetDBInstanceClass(DBInstanceClassServerless).
+func (s *rdsAurora) SetServerlessV2Scaling(min, max float64) Aurora {
+	scaling := newServerlessV2Scaling(min, max)
+	s.createClusterParam.ServerlessV2ScalingConfiguration = scaling
+	s.restoreClusterPitrParam.ServerlessV2ScalingConfiguration = scaling
+	s.restoreClusterFromSnapshotParam.ServerlessV2ScalingConfiguration = scaling
+	s.modifyClusterParam.ServerlessV2ScalingConfiguration = scaling
+	return s
+}
+
+func convertServerlessV2Scaling(in *types.ServerlessV2ScalingConfigurationInfo) *ServerlessV2Scaling {
+	if in == nil {
+		return nil
+	}
+	return &ServerlessV2Scaling{
+		MinCapacity: aws.ToFloat64(in.MinCapacity),
+		MaxCapacity: aws.ToFloat64(in.MaxCapacity),
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Serverless", func() {
+	It("should accept the engine versions supporting serverless v2", func() {
+		Expect(ValidateServerlessV2("aurora-mysql", "8.0.mysql_aurora.3.02.0", 0.5, 16)).To(Succeed())
+		Expect(ValidateServerlessV2("aurora-mysql", "8.0.mysql_aurora.3.04.1", 2, 128)).To(Succeed())
+		Expect(ValidateServerlessV2("aurora-postgresql", "14.3", 0.5, 1)).To(Succeed())
+		Expect(ValidateServerlessV2("aurora-postgresql", "16.1", 0.5, 1)).To(Succeed())
+		Expect(ValidateServerlessV2("aurora-postgresql", "", 0.5, 1)).To(Succeed())
+	})
+
+	It("should reject the unsupported engines, versions and capacities", func() {
+		Expect(ValidateServerlessV2("aurora-mysql", "8.0.mysql_aurora.3.01.1", 0.5, 16)).To(MatchError(ErrServerlessUnsupported))
+		Expect(ValidateServerlessV2("aurora-mysql", "5.7.mysql_aurora.2.11.2", 0.5, 16)).To(MatchError(ErrServerlessUnsupported))
+		Expect(ValidateServerlessV2("aurora-postgresql", "13.5", 0.5, 16)).To(MatchError(ErrServerlessUnsupported))
+		Expect(ValidateServerlessV2("aurora-postgresql", "12.9", 0.5, 16)).To(MatchError(ErrServerlessUnsupported))
+		Expect(ValidateServerlessV2("mysql", "8.0.32", 0.5, 16)).To(MatchError(ErrServerlessUnsupported))
+
+		Expect(ValidateServerlessV2("aurora-postgresql", "14.3", 0, 16)).ToNot(Succeed())
+		Expect(ValidateServerlessV2("aurora-postgresql", "14.3", 8, 4)).ToNot(Succeed())
+		Expect(ValidateServerlessV2("aurora-postgresql", "14.3", 0.5, 256)).ToNot(Succeed())
+		Expect(ValidateServerlessV2("aurora-postgresql", "14.3", 0.75, 4)).ToNot(Succeed())
+	})
+
+	It("should validate the serverless instances of an aurora cluster before creating it", func() {
+		ctx := context.Background()
+		a := newAurora(nil)
+		a.SetEngine("aurora-postgresql").SetEngineVersion("14.6").SetDBInstanceClass(DBInstanceClassServerless)
+		Expect(a.Create(ctx)).To(MatchError(ContainSubstring("require the serverless v2 scaling")))
+
+		a.SetEngineVersion("13.4").SetServerlessV2Scaling(0.5, 8)
+		Expect(a.CreateWithPrimary(ctx)).To(MatchError(ErrServerlessUnsupported))
+		Expect(aws.ToFloat64(a.modifyClusterParam.ServerlessV2ScalingConfiguration.MaxCapacity)).To(Equal(8.0))
+
+		c := newCluster(nil)
+		c.SetEngine("mysql").SetDBClusterInstanceClass(DBInstanceClassServerless)
+		Expect(c.Create(ctx)).To(MatchError(ErrServerlessUnsupported))
+		Expect(c.SetServerlessV2Scaling(4, 2).Modify(ctx)).ToNot(Succeed())
+	})
+
+	It("should report the scaling of the cluster", func() {
+		desc := convertDBCluster(&types.DBCluster{
+			EngineMode: aws.String("provisioned"),
+			ServerlessV2ScalingConfiguration: &types.ServerlessV2ScalingConfigurationInfo{
+				MinCapacity: aws.Float64(0.5),
+				MaxCapacity: aws.Float64(16),
+			},
+		})
+		Expect(desc.ServerlessV2Scaling).To(Equal(&ServerlessV2Scaling{MinCapacity: 0.5, MaxCapacity: 16}))
+		Expect(convertDBCluster(&types.DBCluster{}).ServerlessV2Scaling).To(BeNil())
+	})
+})
@@ -0,0 +1,257 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+const (
+	SnapshotStatusAvailable = "available"
+	SnapshotStatusCreating  = "creating"
+	SnapshotStatusCopying   = "copying"
+	SnapshotStatusDeleting  = "deleting"
+	SnapshotStatusFailed    = "failed"
+
+	// SnapshotShareAll shares a snapshot publicly with every account.
+	SnapshotShareAll = "all"
+
+	snapshotRestoreAttribute = "restore"
+)
+
+func isTerminalSnapshotStatus(status string) bool {
+	return status == SnapshotStatusFailed || status == SnapshotStatusDeleting || strings.HasPrefix(status, "incompatible-")
+}
+
+// SetTargetSnapshotIdentifier sets the identifier of the copy made by CopySnapshot.
+func (s *rdsInstance) SetTargetSnapshotIdentifier(id string) Instance {
+	s.copySnapshotParam.TargetDBSnapshotIdentifier = aws.String(id)
+	return s
+}
+
+// SetCopyTags copies the tags of the source snapshot to the copy.
+func (s *rdsInstance) SetCopyTags(enable bool) Instance {
+	s.copySnapshotParam.CopyTags = aws.Bool(enable)
+	return s
+}
+
+// DeleteSnapshot deletes the snapshot set by SetSnapshotIdentifier.
+func (s *rdsInstance) DeleteSnapshot(ctx context.Context) error {
+	_, err := s.core.DeleteDBSnapshot(ctx, s.deleteSnapshotParam)
+	return wrapError(err)
+}
+
+// CopySnapshot copies the snapshot set by SetSnapshotIdentifier to the one
+// set by SetTargetSnapshotIdentifier. For a cross-region copy the builder is
+// created in the destination region, the source is set by ARN together with
+// SetSourceRegion, and SetKmsKeyId sets the key of the destination region.
+func (s *rdsInstance) CopySnapshot(ctx context.Context) error {
+	_, err := s.core.CopyDBSnapshot(ctx, s.copySnapshotParam)
+	return wrapError(err)
+}
+
+// WaitForSnapshot waits until the copy set by SetTargetSnapshotIdentifier, or
+// else the snapshot set by SetSnapshotIdentifier, is available.
+func (s *rdsInstance) WaitForSnapshot(ctx context.Context, opts *WaitOptions) error {
+	id := s.copySnapshotParam.TargetDBSnapshotIdentifier
+	if id == nil {
+		id = s.describeSnapshotParam.DBSnapshotIdentifier
+	}
+	if id == nil {
+		return errors.New("db snapshot identifier is required")
+	}
+	param := &rds.DescribeDBSnapshotsInput{DBSnapshotIdentifier: id}
+	return waitFor(ctx, *id, SnapshotStatusAvailable, opts, func(ctx context.Context) (string, error) {
+		out, err := s.core.DescribeDBSnapshots(ctx, param)
+		if err != nil {
+			return "", wrapError(err)
+		}
+		if len(out.DBSnapshots) == 0 {
+			return "", fmt.Errorf("db snapshot %s: %w", *id, ErrNotFound)
+		}
+		return aws.ToString(out.DBSnapshots[0].Status), nil
+	}, isTerminalSnapshotStatus)
+}
+
+// SetTargetSnapshotIdentifier sets the identifier of the copy made by CopySnapshot.
+func (s *rdsCluster) SetTargetSnapshotIdentifier(id string) Cluster {
+	s.copyClusterSnapshotParam.TargetDBClusterSnapshotIdentifier = aws.String(id)
+	return s
+}
+
+// SetCopyTags copies the tags of the source snapshot to the copy.
+func (s *rdsCluster) SetCopyTags(enable bool) Cluster {
+	s.copyClusterSnapshotParam.CopyTags = aws.Bool(enable)
+	return s
+}
+
+// SetKmsKeyId sets the key encrypting a snapshot copy, in the destination region.
+func (s *rdsCluster) SetKmsKeyId(id string) Cluster {
+	s.copyClusterSnapshotParam.KmsKeyId = aws.String(id)
+	return s
+}
+
+// SetSourceRegion sets the region of the source snapshot of a cross-region
+// copy. The presigned url is generated from it by the sdk.
+func (s *rdsCluster) SetSourceRegion(region string) Cluster {
+	s.copyClusterSnapshotParam.SourceRegion = aws.String(region)
+	return s
+}
+
+// DeleteSnapshot deletes the snapshot set by SetSnapshotIdentifier.
+func (s *rdsCluster) DeleteSnapshot(ctx context.Context) error {
+	_, err := s.core.DeleteDBClusterSnapshot(ctx, s.deleteClusterSnapshotParam)
+	return wrapError(err)
+}
+
+// CopySnapshot copies the snapshot set by SetSnapshotIdentifier to the one
+// set by SetTargetSnapshotIdentifier. For a cross-region copy the builder is
+// created in the destination region, the source is set by ARN together with
+// SetSourceRegion, and SetKmsKeyId sets the key of the destination region.
+func (s *rdsCluster) CopySnapshot(ctx context.Context) error {
+	_, err := s.core.CopyDBClusterSnapshot(ctx, s.copyClusterSnapshotParam)
+	return wrapError(err)
+}
+
+// WaitForSnapshot waits until the copy set by SetTargetSnapshotIdentifier, or
+// else the snapshot set by SetSnapshotIdentifier, is available.
+func (s *rdsCluster) WaitForSnapshot(ctx context.Context, opts *WaitOptions) error {
+	return waitForClusterSnapshot(ctx, s.core, s.copyClusterSnapshotParam.TargetDBClusterSnapshotIdentifier, s.describeDBClusterSnapshotParam.DBClusterSnapshotIdentifier, opts)
+}
+
+// SetTargetSnapshotIdentifier sets the identifier of the copy made by CopySnapshot.
+func (s *rdsAurora) SetTargetSnapshotIdentifier(id string) Aurora {
+	s.copyClusterSnapshotParam.TargetDBClusterSnapshotIdentifier = aws.String(id)
+	return s
+}
+
+// SetCopyTags copies the tags of the source snapshot to the copy.
+func (s *rdsAurora) SetCopyTags(enable bool) Aurora {
+	s.copyClusterSnapshotParam.CopyTags = aws.Bool(enable)
+	return s
+}
+
+// SetKmsKeyId sets the key encrypting a snapshot copy, in the destination region.
+func (s *rdsAurora) SetKmsKeyId(id string) Aurora {
+	s.copyClusterSnapshotParam.KmsKeyId = aws.String(id)
+	return s
+}
+
+// SetSourceRegion sets the region of the source snapshot of a cross-region
+// copy. The presigned url is generated from it by the sdk.
+func (s *rdsAurora) SetSourceRegion(region string) Aurora {
+	s.copyClusterSnapshotParam.SourceRegion = aws.String(region)
+	return s
+}
+
+// DeleteSnapshot deletes the snapshot set by SetSnapshotIdentifier.
+func (s *rdsAurora) DeleteSnapshot(ctx context.Context) error {
+	_, err := s.core.DeleteDBClusterSnapshot(ctx, s.deleteClusterSnapshotParam)
+	return wrapError(err)
+}
+
+// CopySnapshot copies the snapshot set by SetSnapshotIdentifier to the one
+// set by SetTargetSnapshotIdentifier. For a cross-region copy the builder is
+// created in the destination region, the source is set by ARN together with
+// SetSourceRegion, and SetKmsKeyId sets the key of the destination region.
+func (s *rdsAurora) CopySnapshot(ctx context.Context) error {
+	_, err := s.core.CopyDBClusterSnapshot(ctx, s.copyClusterSnapshotParam)
+	return wrapError(err)
+}
+
+// WaitForSnapshot waits until the copy set by SetTargetSnapshotIdentifier, or
+// else the snapshot set by SetSnapshotIdentifier, is available.
+func (s *rdsAurora) WaitForSnapshot(ctx context.Context, opts *WaitOptions) error {
+	return waitForClusterSnapshot(ctx, s.core, s.copyClusterSnapshotParam.TargetDBClusterSnapshotIdentifier, s.describeClusterSnapshotParam.DBClusterSnapshotIdentifier, opts)
+}
+
+// waitForClusterSnapshot waits until the snapshot target, or else source, is
+// available. A missing snapshot is an error rather than a status to wait for.
+func waitForClusterSnapshot(ctx context.Context, core *rds.Client, target, source *string, opts *WaitOptions) error {
+	id := target
+	if id == nil {
+		id = source
+	}
+	if id == nil {
+		return errors.New("db cluster snapshot identifier is required")
+	}
+	param := &rds.DescribeDBClusterSnapshotsInput{DBClusterSnapshotIdentifier: id}
+	return waitFor(ctx, *id, SnapshotStatusAvailable, opts, func(ctx context.Context) (string, error) {
+		out, err := core.DescribeDBClusterSnapshots(ctx, param)
+		if err != nil {
+			return "", wrapError(err)
+		}
+		if len(out.DBClusterSnapshots) == 0 {
+			return "", fmt.Errorf("db cluster snapshot %s: %w", *id, ErrNotFound)
+		}
+		return aws.ToString(out.DBClusterSnapshots[0].Status), nil
+	}, isTerminalSnapshotStatus)
+}
+
+// ShareSnapshot allows accounts, or SnapshotShareAll, to copy and restore the
+// manual snapshot set by SetSnapshotIdentifier.
+func (s *rdsInstance) ShareSnapshot(ctx context.Context, accounts ...string) error {
+	_, err := s.core.ModifyDBSnapshotAttribute(ctx, &rds.ModifyDBSnapshotAttributeInput{
+		DBSnapshotIdentifier: s.describeSnapshotParam.DBSnapshotIdentifier,
+		AttributeName:        aws.String(snapshotRestoreAttribute),
+		ValuesToAdd:          accounts,
+	})
+	return wrapError(err)
+}
+
+func (s *rdsInstance) UnshareSnapshot(ctx context.Context, accounts ...string) error {
+	_, err := s.core.ModifyDBSnapshotAttribute(ctx, &rds.ModifyDBSnapshotAttributeInput{
+		DBSnapshotIdentifier: s.describeSnapshotParam.DBSnapshotIdentifier,
+		AttributeName:        aws.String(snapshotRestoreAttribute),
+		ValuesToRemove:       accounts,
+	})
+	return wrapError(err)
+}
+
+// ShareSnapshot allows accounts, or SnapshotShareAll, to copy and restore the
+// manual snapshot set by SetSnapshotIdentifier.
+func (s *rdsCluster) ShareSnapshot(ctx context.Context, accounts ...string) error {
+	return shareClusterSnapshot(ctx, s.core, s.describeDBClusterSnapshotParam.DBClusterSnapshotIdentifier, accounts, nil)
+}
+
+func (s *rdsCluster) UnshareSnapshot(ctx context.Context, accounts ...string) error {
+	return shareClusterSnapshot(ctx, s.core, s.describeDBClusterSnapshotParam.DBClusterSnapshotIdentifier, nil, accounts)
+}
+
+// ShareSnapshot allows accounts, or SnapshotShareAll, to copy and restore the
+// manual snapshot set by SetSnapshotIdentifier.
+func (s *rdsAurora) ShareSnapshot(ctx context.Context, accounts ...string) error {
+	return shareClusterSnapshot(ctx, s.core, s.describeClusterSnapshotParam.DBClusterSnapshotIdentifier, accounts, nil)
+}
+
+func (s *rdsAurora) UnshareSnapshot(ctx context.Context, accounts ...string) error {
+	return shareClusterSnapshot(ctx, s.core, s.describeClusterSnapshotParam.DBClusterSnapshotIdentifier, nil, accounts)
+}
+
+func shareClusterSnapshot(ctx context.Context, core *rds.Client, id *string, add, remove []string) error {
+	_, err := core.ModifyDBClusterSnapshotAttribute(ctx, &rds.ModifyDBClusterSnapshotAttributeInput{
+		DBClusterSnapshotIdentifier: id,
+		AttributeName:               aws.String(snapshotRestoreAttribute),
+		ValuesToAdd:                 add,
+		ValuesToRemove:              remove,
+	})
+	return wrapError(err)
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	It("should feed the copy and delete inputs from the setters", func() {
+		source := "arn:aws:rds:us-east-1:123456789012:cluster-snapshot:foo"
+		b := NewService(aws.Config{Region: "us-west-2"}).Aurora().
+			SetSnapshotIdentifier(source).
+			SetTargetSnapshotIdentifier("foo-copy").
+			SetSourceRegion("us-east-1").
+			SetKmsKeyId("alias/rds").
+			SetCopyTags(true).(*rdsAurora)
+
+		Expect(aws.ToString(b.copyClusterSnapshotParam.SourceDBClusterSnapshotIdentifier)).To(Equal(source))
+		Expect(aws.ToString(b.copyClusterSnapshotParam.TargetDBClusterSnapshotIdentifier)).To(Equal("foo-copy"))
+		Expect(aws.ToString(b.copyClusterSnapshotParam.SourceRegion)).To(Equal("us-east-1"))
+		Expect(aws.ToString(b.copyClusterSnapshotParam.KmsKeyId)).To(Equal("alias/rds"))
+		Expect(aws.ToBool(b.copyClusterSnapshotParam.CopyTags)).To(BeTrue())
+		Expect(aws.ToString(b.deleteClusterSnapshotParam.DBClusterSnapshotIdentifier)).To(Equal(source))
+
+		i := NewService(aws.Config{Region: "us-west-2"}).Instance().
+			SetSnapshotIdentifier("bar").
+			SetKmsKeyId("alias/rds").(*rdsInstance)
+		Expect(aws.ToString(i.copySnapshotParam.SourceDBSnapshotIdentifier)).To(Equal("bar"))
+		Expect(aws.ToString(i.copySnapshotParam.KmsKeyId)).To(Equal("alias/rds"))
+		Expect(aws.ToString(i.deleteSnapshotParam.DBSnapshotIdentifier)).To(Equal("bar"))
+	})
+
+	It("should stop waiting for failed snapshots", func() {
+		Expect(isTerminalSnapshotStatus(SnapshotStatusFailed)).To(BeTrue())
+		Expect(isTerminalSnapshotStatus("incompatible-restore")).To(BeTrue())
+		Expect(isTerminalSnapshotStatus(SnapshotStatusCopying)).To(BeFalse())
+	})
+
+	It("should wait on the copy and fail when the snapshot is missing", func() {
+		ctx := context.Background()
+		core := fakeClient("", `<DescribeDBClusterSnapshotsResponse><DescribeDBClusterSnapshotsResult><DBClusterSnapshots></DBClusterSnapshots></DescribeDBClusterSnapshotsResult></DescribeDBClusterSnapshotsResponse>`)
+		a := newAurora(core)
+		a.SetSnapshotIdentifier("arn:aws:rds:us-east-1:123456789012:cluster-snapshot:foo").SetTargetSnapshotIdentifier("foo-copy")
+		err := a.WaitForSnapshot(ctx, &WaitOptions{Timeout: time.Second})
+		Expect(err).To(MatchError(ErrNotFound))
+		Expect(err).To(MatchError(ContainSubstring("foo-copy")))
+
+		core = fakeClient("", `<DescribeDBSnapshotsResponse><DescribeDBSnapshotsResult><DBSnapshots></DBSnapshots></DescribeDBSnapshotsResult></DescribeDBSnapshotsResponse>`)
+		err = newInstance(core).SetSnapshotIdentifier("bar").WaitForSnapshot(ctx, &WaitOptions{Timeout: time.Second})
+		Expect(err).To(MatchError(ErrNotFound))
+		Expect(err).To(MatchError(ContainSubstring("bar")))
+	})
+})
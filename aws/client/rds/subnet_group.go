@@ -0,0 +1,172 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+type DescSubnetGroup struct {
+	Name                  string
+	Arn                   string
+	Description           string
+	Status                string
+	VpcId                 string
+	Subnets               []Subnet
+	SupportedNetworkTypes []string
+}
+
+type Subnet struct {
+	Identifier       string
+	AvailabilityZone string
+	Status           string
+}
+
+// SubnetGroup manages the DB subnet groups used by SetDBSubnetGroup of
+// Instance and Aurora and SetDBSubnetGroupName of Cluster.
+type SubnetGroup interface {
+	SetName(name string) SubnetGroup
+	SetDescription(desc string) SubnetGroup
+	SetSubnetIds(ids []string) SubnetGroup
+	SetMaxResults(max int32) SubnetGroup
+
+	Create(context.Context) error
+	Modify(context.Context) error
+	Delete(context.Context) error
+	Describe(context.Context) (*DescSubnetGroup, error)
+	List(context.Context) ([]*DescSubnetGroup, error)
+}
+
+type rdsSubnetGroup struct {
+	core *rds.Client
+
+	createSubnetGroupParam    *rds.CreateDBSubnetGroupInput
+	modifySubnetGroupParam    *rds.ModifyDBSubnetGroupInput
+	deleteSubnetGroupParam    *rds.DeleteDBSubnetGroupInput
+	describeSubnetGroupsParam *rds.DescribeDBSubnetGroupsInput
+
+	maxResults int32
+}
+
+func newSubnetGroup(core *rds.Client) *rdsSubnetGroup {
+	return &rdsSubnetGroup{
+		core:                      core,
+		createSubnetGroupParam:    &rds.CreateDBSubnetGroupInput{},
+		modifySubnetGroupParam:    &rds.ModifyDBSubnetGroupInput{},
+		deleteSubnetGroupParam:    &rds.DeleteDBSubnetGroupInput{},
+		describeSubnetGroupsParam: &rds.DescribeDBSubnetGroupsInput{},
+	}
+}
+
+func (s *rdsSubnetGroup) SetName(name string) SubnetGroup {
+	s.createSubnetGroupParam.DBSubnetGroupName = aws.String(name)
+	s.modifySubnetGroupParam.DBSubnetGroupName = aws.String(name)
+	s.deleteSubnetGroupParam.DBSubnetGroupName = aws.String(name)
+	s.describeSubnetGroupsParam.DBSubnetGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsSubnetGroup) SetDescription(desc string) SubnetGroup {
+	s.createSubnetGroupParam.DBSubnetGroupDescription = aws.String(desc)
+	s.modifySubnetGroupParam.DBSubnetGroupDescription = aws.String(desc)
+	return s
+}
+
+// SetSubnetIds sets the subnets of the group, which must span at least two
+// availability zones. Modify replaces the subnets with them.
+func (s *rdsSubnetGroup) SetSubnetIds(ids []string) SubnetGroup {
+	s.createSubnetGroupParam.SubnetIds = ids
+	s.modifySubnetGroupParam.SubnetIds = ids
+	return s
+}
+
+// SetMaxResults caps the number of groups returned by List, 0 for no cap.
+func (s *rdsSubnetGroup) SetMaxResults(max int32) SubnetGroup {
+	s.maxResults = max
+	return s
+}
+
+func (s *rdsSubnetGroup) Create(ctx context.Context) error {
+	_, err := s.core.CreateDBSubnetGroup(ctx, s.createSubnetGroupParam)
+	return wrapError(err)
+}
+
+func (s *rdsSubnetGroup) Modify(ctx context.Context) error {
+	_, err := s.core.ModifyDBSubnetGroup(ctx, s.modifySubnetGroupParam)
+	return wrapError(err)
+}
+
+func (s *rdsSubnetGroup) Delete(ctx context.Context) error {
+	_, err := s.core.DeleteDBSubnetGroup(ctx, s.deleteSubnetGroupParam)
+	return wrapError(err)
+}
+
+// Describe returns the group set by SetName, nil when it does not exist.
+func (s *rdsSubnetGroup) Describe(ctx context.Context) (*DescSubnetGroup, error) {
+	if s.describeSubnetGroupsParam.DBSubnetGroupName == nil {
+		return nil, errors.New("db subnet group name is required")
+	}
+	groups, err := s.List(ctx)
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	return groups[0], nil
+}
+
+// List returns the group set by SetName, or every group of the account.
+func (s *rdsSubnetGroup) List(ctx context.Context) ([]*DescSubnetGroup, error) {
+	return collect(func(fn func(*DescSubnetGroup) bool) error {
+		return ignoreNotFound(paginate(ctx, s.maxResults, func(ctx context.Context, marker *string, pageSize int32) ([]*DescSubnetGroup, *string, error) {
+			param := *s.describeSubnetGroupsParam
+			param.Marker, param.MaxRecords = marker, aws.Int32(pageSize)
+			out, err := s.core.DescribeDBSubnetGroups(ctx, &param)
+			if err != nil {
+				return nil, nil, err
+			}
+			var descs []*DescSubnetGroup
+			for i := range out.DBSubnetGroups {
+				descs = append(descs, convertSubnetGroup(&out.DBSubnetGroups[i]))
+			}
+			return descs, out.Marker, nil
+		}, fn))
+	})
+}
+
+func convertSubnetGroup(in *types.DBSubnetGroup) *DescSubnetGroup {
+	desc := &DescSubnetGroup{
+		Name:                  aws.ToString(in.DBSubnetGroupName),
+		Arn:                   aws.ToString(in.DBSubnetGroupArn),
+		Description:           aws.ToString(in.DBSubnetGroupDescription),
+		Status:                aws.ToString(in.SubnetGroupStatus),
+		VpcId:                 aws.ToString(in.VpcId),
+		SupportedNetworkTypes: in.SupportedNetworkTypes,
+	}
+	for _, subnet := range in.Subnets {
+		sn := Subnet{
+			Identifier: aws.ToString(subnet.SubnetIdentifier),
+			Status:     aws.ToString(subnet.SubnetStatus),
+		}
+		if subnet.SubnetAvailabilityZone != nil {
+			sn.AvailabilityZone = aws.ToString(subnet.SubnetAvailabilityZone.Name)
+		}
+		desc.Subnets = append(desc.Subnets, sn)
+	}
+	return desc
+}
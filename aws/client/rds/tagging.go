@@ -0,0 +1,196 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// TagFilterPrefix prefixes the name of a SetFilter filter selecting by tag,
+// such as tag:owner. The instances or clusters are kept when the tag has one
+// of the values, or any value when no value is given. Tag filters are applied
+// to every page returned by rds, as the describe APIs do not support them.
+const TagFilterPrefix = "tag:"
+
+// Tagging manages the tags of any rds resource by ARN.
+type Tagging interface {
+	SetResourceArn(arn string) Tagging
+	SetTags(tags map[string]string) Tagging
+	SetTagKeys(keys []string) Tagging
+
+	// AddTags adds the tags set by SetTags, overwriting the existing values.
+	AddTags(context.Context) error
+	// RemoveTags removes the tags set by SetTagKeys.
+	RemoveTags(context.Context) error
+	ListTags(context.Context) (map[string]string, error)
+}
+
+type rdsTagging struct {
+	core *rds.Client
+
+	addTagsParam    *rds.AddTagsToResourceInput
+	removeTagsParam *rds.RemoveTagsFromResourceInput
+	listTagsParam   *rds.ListTagsForResourceInput
+}
+
+func newTagging(core *rds.Client) *rdsTagging {
+	return &rdsTagging{
+		core:            core,
+		addTagsParam:    &rds.AddTagsToResourceInput{},
+		removeTagsParam: &rds.RemoveTagsFromResourceInput{},
+		listTagsParam:   &rds.ListTagsForResourceInput{},
+	}
+}
+
+func (s *rdsTagging) SetResourceArn(arn string) Tagging {
+	s.addTagsParam.ResourceName = aws.String(arn)
+	s.removeTagsParam.ResourceName = aws.String(arn)
+	s.listTagsParam.ResourceName = aws.String(arn)
+	return s
+}
+
+func (s *rdsTagging) SetTags(tags map[string]string) Tagging {
+	s.addTagsParam.Tags = toTags(tags)
+	return s
+}
+
+func (s *rdsTagging) SetTagKeys(keys []string) Tagging {
+	s.removeTagsParam.TagKeys = keys
+	return s
+}
+
+func (s *rdsTagging) AddTags(ctx context.Context) error {
+	_, err := s.core.AddTagsToResource(ctx, s.addTagsParam)
+	return wrapError(err)
+}
+
+func (s *rdsTagging) RemoveTags(ctx context.Context) error {
+	_, err := s.core.RemoveTagsFromResource(ctx, s.removeTagsParam)
+	return wrapError(err)
+}
+
+func (s *rdsTagging) ListTags(ctx context.Context) (map[string]string, error) {
+	out, err := s.core.ListTagsForResource(ctx, s.listTagsParam)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return convertTags(out.TagList), nil
+}
+
+// SetTags sets the tags of the instance created by Create, RestoreFromSnapshot,
+// RestoreToPitr and CreateReadReplica.
+func (s *rdsInstance) SetTags(tags map[string]string) Instance {
+	t := toTags(tags)
+	s.createInstanceParam.Tags = t
+	s.restoreInstancePitrParam.Tags = t
+	s.restoreFromSnapshotParam.Tags = t
+	s.createReadReplicaParam.Tags = t
+	return s
+}
+
+// SetTags sets the tags of the cluster created by Create, RestoreFromSnapshot
+// and RestoreToPitr.
+func (s *rdsCluster) SetTags(tags map[string]string) Cluster {
+	t := toTags(tags)
+	s.createClusterParam.Tags = t
+	s.restoreDBClusterPitrParam.Tags = t
+	s.restoreDBClusterFromSnapshotParam.Tags = t
+	return s
+}
+
+// SetTags sets the tags of the cluster created by Create, RestoreFromSnapshot
+// and RestoreToPitr, and of the instances created with it.
+func (s *rdsAurora) SetTags(tags map[string]string) Aurora {
+	t := toTags(tags)
+	s.createClusterParam.Tags = t
+	s.restoreClusterFromSnapshotParam.Tags = t
+	s.restoreClusterPitrParam.Tags = t
+	s.createInstanceParam.Tags = t
+	s.restoreInstancePitrParam.Tags = t
+	return s
+}
+
+// toTags converts tags to rds tags sorted by key.
+func toTags(tags map[string]string) []types.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]types.Tag, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return out
+}
+
+// setDescribeFilter sets the filter name of filters, or of tagFilters when
+// name starts with TagFilterPrefix.
+func setDescribeFilter(filters, tagFilters []types.Filter, name string, values []string) ([]types.Filter, []types.Filter) {
+	if strings.HasPrefix(name, TagFilterPrefix) {
+		return filters, setFilter(tagFilters, strings.TrimPrefix(name, TagFilterPrefix), values)
+	}
+	return setFilter(filters, name, values), tagFilters
+}
+
+// convertTags converts rds tags to a map, nil when there is none.
+func convertTags(tags []types.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, t := range tags {
+		out[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return out
+}
+
+// matchTag reports whether tags has key, with value unless value is empty.
+func matchTag(tags map[string]string, key, value string) bool {
+	v, ok := tags[key]
+	return ok && (value == "" || v == value)
+}
+
+// matchTagFilters reports whether tags match every tag filter, a filter
+// without values matching the key only.
+func matchTagFilters(tags map[string]string, filters []types.Filter) bool {
+	for _, f := range filters {
+		key := aws.ToString(f.Name)
+		if len(f.Values) == 0 {
+			if !matchTag(tags, key, "") {
+				return false
+			}
+			continue
+		}
+		matched := false
+		for _, want := range f.Values {
+			if matchTag(tags, key, want) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tagging", func() {
+	tags := map[string]string{"owner": "dba", "cost-center": "42"}
+
+	It("should set the tags on the create and restore paths", func() {
+		i := newInstance(nil)
+		i.SetTags(tags)
+		Expect(i.createInstanceParam.Tags).To(Equal([]types.Tag{
+			{Key: aws.String("cost-center"), Value: aws.String("42")},
+			{Key: aws.String("owner"), Value: aws.String("dba")},
+		}))
+		Expect(i.restoreFromSnapshotParam.Tags).To(HaveLen(2))
+		Expect(i.createReadReplicaParam.Tags).To(HaveLen(2))
+
+		a := newAurora(nil)
+		a.SetTags(tags)
+		Expect(a.restoreClusterPitrParam.Tags).To(HaveLen(2))
+		Expect(a.createInstanceParam.Tags).To(HaveLen(2))
+
+		desc := convertDBCluster(&types.DBCluster{TagList: a.createClusterParam.Tags})
+		Expect(desc.Tags).To(Equal(tags))
+	})
+
+	It("should keep tag filters out of the describe filters", func() {
+		c := newCluster(nil)
+		c.SetFilter("engine", []string{"aurora-mysql"}).
+			SetFilter("tag:owner", []string{"dba", "sre"}).
+			SetFilter("tag:cost-center", nil)
+
+		Expect(c.describeClusterParam.Filters).To(HaveLen(1))
+		Expect(c.tagFilters).To(HaveLen(2))
+		Expect(matchTagFilters(tags, c.tagFilters)).To(BeTrue())
+		Expect(matchTagFilters(map[string]string{"owner": "dba"}, c.tagFilters)).To(BeFalse())
+		Expect(matchTagFilters(map[string]string{"owner": "dev", "cost-center": "1"}, c.tagFilters)).To(BeFalse())
+		Expect(matchTagFilters(nil, nil)).To(BeTrue())
+	})
+})
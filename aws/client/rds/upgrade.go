@@ -0,0 +1,402 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+const defaultParameterGroupPrefix = "default."
+
+var (
+	ErrInvalidUpgradeTarget = errors.New("engine version is not a valid upgrade target")
+	// ErrParameterGroupFamily is returned when the parameter group of an
+	// upgrade is not of the family of the target engine version.
+	ErrParameterGroupFamily = errors.New("parameter group family does not match the engine version")
+)
+
+type UpgradeTarget struct {
+	Engine                string
+	EngineVersion         string
+	Description           string
+	AutoUpgrade           bool
+	IsMajorVersionUpgrade bool
+}
+
+// EngineUpgrade upgrades the engine version of an instance or a cluster. The
+// target is checked against the valid upgrade targets of the current version,
+// and the parameter groups against the family of the target, then a snapshot
+// is taken before modifying the engine version.
+type EngineUpgrade interface {
+	SetDBInstanceIdentifier(id string) EngineUpgrade
+	SetDBClusterIdentifier(id string) EngineUpgrade
+	SetEngineVersion(version string) EngineUpgrade
+	// SetDBParameterGroupName sets the parameter group of the instance, or of
+	// the instances of the cluster, after the upgrade.
+	SetDBParameterGroupName(name string) EngineUpgrade
+	SetDBClusterParameterGroupName(name string) EngineUpgrade
+	SetApplyImmediately(enable bool) EngineUpgrade
+	// SetSnapshotIdentifier sets the snapshot taken before the upgrade,
+	// <identifier>-pre-upgrade-<time> by default. The upgrade fails when a
+	// snapshot of the identifier already exists.
+	SetSnapshotIdentifier(id string) EngineUpgrade
+	SetSkipSnapshot(skip bool) EngineUpgrade
+	SetSnapshotWaitOptions(opts *WaitOptions) EngineUpgrade
+
+	// ValidTargets returns the versions the current engine version can be upgraded to.
+	ValidTargets(context.Context) ([]UpgradeTarget, error)
+	Upgrade(context.Context) error
+}
+
+type rdsEngineUpgrade struct {
+	core *rds.Client
+
+	modifyInstanceParam *rds.ModifyDBInstanceInput
+	modifyClusterParam  *rds.ModifyDBClusterInput
+
+	instanceID          string
+	clusterID           string
+	snapshotID          string
+	skipSnapshot        bool
+	snapshotWaitOptions *WaitOptions
+}
+
+func newEngineUpgrade(core *rds.Client) *rdsEngineUpgrade {
+	return &rdsEngineUpgrade{
+		core:                core,
+		modifyInstanceParam: &rds.ModifyDBInstanceInput{},
+		modifyClusterParam:  &rds.ModifyDBClusterInput{},
+	}
+}
+
+func (s *rdsEngineUpgrade) SetDBInstanceIdentifier(id string) EngineUpgrade {
+	s.instanceID = id
+	s.modifyInstanceParam.DBInstanceIdentifier = aws.String(id)
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetDBClusterIdentifier(id string) EngineUpgrade {
+	s.clusterID = id
+	s.modifyClusterParam.DBClusterIdentifier = aws.String(id)
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetEngineVersion(version string) EngineUpgrade {
+	s.modifyInstanceParam.EngineVersion = aws.String(version)
+	s.modifyClusterParam.EngineVersion = aws.String(version)
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetDBParameterGroupName(name string) EngineUpgrade {
+	s.modifyInstanceParam.DBParameterGroupName = aws.String(name)
+	s.modifyClusterParam.DBInstanceParameterGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetDBClusterParameterGroupName(name string) EngineUpgrade {
+	s.modifyClusterParam.DBClusterParameterGroupName = aws.String(name)
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetApplyImmediately(enable bool) EngineUpgrade {
+	s.modifyInstanceParam.ApplyImmediately = enable
+	s.modifyClusterParam.ApplyImmediately = enable
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetSnapshotIdentifier(id string) EngineUpgrade {
+	s.snapshotID = id
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetSkipSnapshot(skip bool) EngineUpgrade {
+	s.skipSnapshot = skip
+	return s
+}
+
+func (s *rdsEngineUpgrade) SetSnapshotWaitOptions(opts *WaitOptions) EngineUpgrade {
+	s.snapshotWaitOptions = opts
+	return s
+}
+
+// upgradeSource is the engine and the parameter groups of the upgraded
+// resource, the ones of the cluster members included.
+type upgradeSource struct {
+	engine                    string
+	engineVersion             string
+	parameterGroupName        string
+	clusterParameterGroupName string
+	memberParameterGroupNames []string
+}
+
+func (s *rdsEngineUpgrade) validate() error {
+	if (s.instanceID == "") == (s.clusterID == "") {
+		return errors.New("either db instance identifier or db cluster identifier is required")
+	}
+	return nil
+}
+
+func (s *rdsEngineUpgrade) source(ctx context.Context) (*upgradeSource, error) {
+	if s.clusterID != "" {
+		desc, err := newCluster(s.core).SetDBClusterIdentifier(s.clusterID).Describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			return nil, fmt.Errorf("db cluster %s: %w", s.clusterID, ErrNotFound)
+		}
+		src := &upgradeSource{
+			engine:                    desc.Engine,
+			engineVersion:             desc.EngineVersion,
+			clusterParameterGroupName: desc.DBClusterParameterGroup,
+		}
+		seen := map[string]bool{}
+		err = iterateInstances(ctx, s.core, &rds.DescribeDBInstancesInput{
+			Filters: setFilter(nil, "db-cluster-id", []string{s.clusterID}),
+		}, nil, 0, func(ins *DescInstance) bool {
+			for _, g := range ins.DBParameterGroups {
+				if !seen[g.Name] {
+					seen[g.Name] = true
+					src.memberParameterGroupNames = append(src.memberParameterGroupNames, g.Name)
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		return src, nil
+	}
+
+	desc, err := newInstance(s.core).SetDBInstanceIdentifier(s.instanceID).Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, fmt.Errorf("db instance %s: %w", s.instanceID, ErrNotFound)
+	}
+	src := &upgradeSource{engine: desc.Engine, engineVersion: desc.EngineVersion}
+	if len(desc.DBParameterGroups) > 0 {
+		src.parameterGroupName = desc.DBParameterGroups[0].Name
+	}
+	return src, nil
+}
+
+func (s *rdsEngineUpgrade) ValidTargets(ctx context.Context) ([]UpgradeTarget, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	src, err := s.source(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.validTargets(ctx, src)
+}
+
+func (s *rdsEngineUpgrade) validTargets(ctx context.Context, src *upgradeSource) ([]UpgradeTarget, error) {
+	out, err := s.core.DescribeDBEngineVersions(ctx, &rds.DescribeDBEngineVersionsInput{
+		Engine:        aws.String(src.engine),
+		EngineVersion: aws.String(src.engineVersion),
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	var targets []UpgradeTarget
+	for _, v := range out.DBEngineVersions {
+		for _, t := range v.ValidUpgradeTarget {
+			targets = append(targets, UpgradeTarget{
+				Engine:                aws.ToString(t.Engine),
+				EngineVersion:         aws.ToString(t.EngineVersion),
+				Description:           aws.ToString(t.Description),
+				AutoUpgrade:           t.AutoUpgrade,
+				IsMajorVersionUpgrade: t.IsMajorVersionUpgrade,
+			})
+		}
+	}
+	return targets, nil
+}
+
+// parameterGroupFamily returns the parameter group family of the engine version.
+func (s *rdsEngineUpgrade) parameterGroupFamily(ctx context.Context, engine, version string) (string, error) {
+	out, err := s.core.DescribeDBEngineVersions(ctx, &rds.DescribeDBEngineVersionsInput{
+		Engine:        aws.String(engine),
+		EngineVersion: aws.String(version),
+	})
+	if err != nil {
+		return "", wrapError(err)
+	}
+	if len(out.DBEngineVersions) == 0 {
+		return "", fmt.Errorf("engine version %s %s: %w", engine, version, ErrNotFound)
+	}
+	return aws.ToString(out.DBEngineVersions[0].DBParameterGroupFamily), nil
+}
+
+// checkParameterGroup checks that the parameter group name, or else the
+// current one, is of family. A current default group is replaced by the
+// default group of the target by the upgrade itself.
+func (s *rdsEngineUpgrade) checkParameterGroup(ctx context.Context, name *string, current, family string, cluster bool) error {
+	if name == nil {
+		if current == "" || strings.HasPrefix(current, defaultParameterGroupPrefix) {
+			return nil
+		}
+		name = aws.String(current)
+	}
+	desc, err := newParameterGroup(s.core).SetName(*name).SetCluster(cluster).Describe(ctx)
+	if err != nil {
+		return err
+	}
+	if desc == nil {
+		return fmt.Errorf("parameter group %s: %w", *name, ErrNotFound)
+	}
+	return checkParameterGroupFamily(desc.Name, desc.Family, family)
+}
+
+// Upgrade checks the target and the parameter groups, takes the snapshot and
+// then modifies the engine version, allowing a major version upgrade when the
+// target is one.
+func (s *rdsEngineUpgrade) Upgrade(ctx context.Context) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	version := aws.ToString(s.modifyInstanceParam.EngineVersion)
+	if version == "" {
+		return errors.New("engine version is required")
+	}
+
+	src, err := s.source(ctx)
+	if err != nil {
+		return err
+	}
+	targets, err := s.validTargets(ctx, src)
+	if err != nil {
+		return err
+	}
+	target, err := findUpgradeTarget(targets, src.engineVersion, version)
+	if err != nil {
+		return err
+	}
+
+	family, err := s.parameterGroupFamily(ctx, src.engine, version)
+	if err != nil {
+		return err
+	}
+	if s.clusterID != "" {
+		if err := s.checkParameterGroup(ctx, s.modifyClusterParam.DBClusterParameterGroupName, src.clusterParameterGroupName, family, true); err != nil {
+			return err
+		}
+		if name := s.modifyClusterParam.DBInstanceParameterGroupName; name != nil {
+			if err := s.checkParameterGroup(ctx, name, "", family, false); err != nil {
+				return err
+			}
+		} else {
+			for _, current := range src.memberParameterGroupNames {
+				if err := s.checkParameterGroup(ctx, nil, current, family, false); err != nil {
+					return err
+				}
+			}
+		}
+	} else if err := s.checkParameterGroup(ctx, s.modifyInstanceParam.DBParameterGroupName, src.parameterGroupName, family, false); err != nil {
+		return err
+	}
+
+	if !s.skipSnapshot {
+		if err := s.snapshot(ctx); err != nil {
+			return err
+		}
+	}
+
+	if s.clusterID != "" {
+		param := *s.modifyClusterParam
+		param.AllowMajorVersionUpgrade = target.IsMajorVersionUpgrade
+		_, err = s.core.ModifyDBCluster(ctx, &param)
+		return wrapError(err)
+	}
+	param := *s.modifyInstanceParam
+	param.AllowMajorVersionUpgrade = target.IsMajorVersionUpgrade
+	_, err = s.core.ModifyDBInstance(ctx, &param)
+	return wrapError(err)
+}
+
+// snapshot takes the snapshot of the resource and waits until it is
+// available. An existing snapshot of the identifier fails the upgrade, as it
+// may predate it.
+func (s *rdsEngineUpgrade) snapshot(ctx context.Context) error {
+	id := s.snapshotID
+	if id == "" {
+		id = upgradeSnapshotIdentifier(s.instanceID+s.clusterID, time.Now())
+	}
+	if s.clusterID != "" {
+		c := newCluster(s.core).SetDBClusterIdentifier(s.clusterID).SetSnapshotIdentifier(id)
+		desc, err := c.DescribeSnapshot(ctx)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if desc != nil {
+			return fmt.Errorf("db cluster snapshot %s: %w", id, ErrAlreadyExists)
+		}
+		_, err = s.core.CreateDBClusterSnapshot(ctx, &rds.CreateDBClusterSnapshotInput{
+			DBClusterIdentifier:         aws.String(s.clusterID),
+			DBClusterSnapshotIdentifier: aws.String(id),
+		})
+		if err != nil {
+			return wrapError(err)
+		}
+		return c.WaitForSnapshot(ctx, s.snapshotWaitOptions)
+	}
+
+	ins := newInstance(s.core).SetDBInstanceIdentifier(s.instanceID).SetSnapshotIdentifier(id)
+	desc, err := ins.DescribeSnapshot(ctx)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if desc != nil {
+		return fmt.Errorf("db snapshot %s: %w", id, ErrAlreadyExists)
+	}
+	_, err = s.core.CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: aws.String(s.instanceID),
+		DBSnapshotIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return wrapError(err)
+	}
+	return ins.WaitForSnapshot(ctx, s.snapshotWaitOptions)
+}
+
+func upgradeSnapshotIdentifier(id string, now time.Time) string {
+	return fmt.Sprintf("%s-pre-upgrade-%s", id, now.UTC().Format("20060102150405"))
+}
+
+func findUpgradeTarget(targets []UpgradeTarget, current, version string) (*UpgradeTarget, error) {
+	for i := range targets {
+		if targets[i].EngineVersion == version {
+			return &targets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s to %s", ErrInvalidUpgradeTarget, current, version)
+}
+
+func checkParameterGroupFamily(name, groupFamily, family string) error {
+	if groupFamily != family {
+		return fmt.Errorf("%w: %s is of %s, not %s", ErrParameterGroupFamily, name, groupFamily, family)
+	}
+	return nil
+}
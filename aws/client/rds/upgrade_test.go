@@ -0,0 +1,134 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EngineUpgrade", func() {
+	targets := []UpgradeTarget{
+		{Engine: "mysql", EngineVersion: "8.0.33"},
+		{Engine: "mysql", EngineVersion: "8.0.35", AutoUpgrade: true},
+	}
+
+	It("should find the valid upgrade targets only", func() {
+		t, err := findUpgradeTarget(targets, "8.0.32", "8.0.35")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(t.AutoUpgrade).To(BeTrue())
+
+		_, err = findUpgradeTarget(targets, "8.0.32", "8.0.31")
+		Expect(err).To(MatchError(ErrInvalidUpgradeTarget))
+		Expect(err).To(MatchError(ContainSubstring("8.0.32 to 8.0.31")))
+	})
+
+	It("should check the parameter group family", func() {
+		Expect(checkParameterGroupFamily("custom-mysql80", "mysql8.0", "mysql8.0")).To(Succeed())
+		Expect(checkParameterGroupFamily("custom-mysql57", "mysql5.7", "mysql8.0")).To(MatchError(ErrParameterGroupFamily))
+	})
+
+	It("should feed the setters to the instance and cluster params", func() {
+		u := newEngineUpgrade(nil)
+		u.SetDBClusterIdentifier("test-cluster").SetEngineVersion("8.0.mysql_aurora.3.04.0").
+			SetDBParameterGroupName("custom-aurora-mysql80").SetDBClusterParameterGroupName("custom-cluster-aurora-mysql80").SetApplyImmediately(true)
+		Expect(aws.ToString(u.modifyClusterParam.EngineVersion)).To(Equal("8.0.mysql_aurora.3.04.0"))
+		Expect(aws.ToString(u.modifyClusterParam.DBInstanceParameterGroupName)).To(Equal("custom-aurora-mysql80"))
+		Expect(aws.ToString(u.modifyClusterParam.DBClusterParameterGroupName)).To(Equal("custom-cluster-aurora-mysql80"))
+		Expect(u.modifyClusterParam.ApplyImmediately).To(BeTrue())
+		Expect(aws.ToString(u.modifyInstanceParam.DBParameterGroupName)).To(Equal("custom-aurora-mysql80"))
+	})
+
+	It("should require exactly one resource and the engine version", func() {
+		ctx := context.Background()
+		Expect(newEngineUpgrade(nil).SetEngineVersion("8.0.35").Upgrade(ctx)).ToNot(Succeed())
+		Expect(newEngineUpgrade(nil).SetDBInstanceIdentifier("a").SetDBClusterIdentifier("b").Upgrade(ctx)).ToNot(Succeed())
+		Expect(newEngineUpgrade(nil).SetDBInstanceIdentifier("a").Upgrade(ctx)).To(MatchError(ContainSubstring("engine version is required")))
+	})
+
+	It("should name the snapshot after the resource and the time", func() {
+		now := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+		Expect(upgradeSnapshotIdentifier("test-instance", now)).To(Equal("test-instance-pre-upgrade-20230501103000"))
+	})
+
+	It("should fail on an existing snapshot before modifying the instance", func() {
+		var actions []string
+		core := serveClient(func(req *http.Request) (int, string) {
+			action := requestAction(req)
+			actions = append(actions, action)
+			return http.StatusOK, strings.Replace(upgradeResponses[action], "custom-aurora-mysql57", "default.mysql8.0", 1)
+		})
+		err := newEngineUpgrade(core).SetDBInstanceIdentifier("test-instance").SetEngineVersion("8.0.35").
+			SetSnapshotIdentifier("test-instance-before-upgrade").Upgrade(context.Background())
+		Expect(err).To(MatchError(ErrAlreadyExists))
+		Expect(actions).ToNot(ContainElement("CreateDBSnapshot"))
+		Expect(actions).ToNot(ContainElement("ModifyDBInstance"))
+	})
+
+	It("should check the parameter groups of the cluster members", func() {
+		var actions []string
+		core := serveClient(func(req *http.Request) (int, string) {
+			action := requestAction(req)
+			actions = append(actions, action)
+			return http.StatusOK, upgradeResponses[action]
+		})
+		err := newEngineUpgrade(core).SetDBClusterIdentifier("test-cluster").SetEngineVersion("8.0.mysql_aurora.3.04.0").
+			Upgrade(context.Background())
+		Expect(err).To(MatchError(ErrParameterGroupFamily))
+		Expect(err).To(MatchError(ContainSubstring("custom-aurora-mysql57")))
+		Expect(actions).ToNot(ContainElement("CreateDBClusterSnapshot"))
+		Expect(actions).ToNot(ContainElement("ModifyDBCluster"))
+	})
+})
+
+// requestAction returns the api action of a request of the rds query protocol.
+func requestAction(req *http.Request) string {
+	body, _ := io.ReadAll(req.Body)
+	form, _ := url.ParseQuery(string(body))
+	return form.Get("Action")
+}
+
+// upgradeResponses answers the calls of an upgrade of test-instance from
+// mysql 8.0.32 and of test-cluster from aurora mysql 2, whose member uses a
+// custom parameter group of the former major version.
+var upgradeResponses = map[string]string{
+	"DescribeDBInstances": `<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances><DBInstance>` +
+		`<DBInstanceIdentifier>test-instance</DBInstanceIdentifier><Engine>mysql</Engine><EngineVersion>8.0.32</EngineVersion>` +
+		`<DBParameterGroups><DBParameterGroup><DBParameterGroupName>custom-aurora-mysql57</DBParameterGroupName></DBParameterGroup></DBParameterGroups>` +
+		`</DBInstance></DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`,
+	"DescribeDBClusters": `<DescribeDBClustersResponse><DescribeDBClustersResult><DBClusters><DBCluster>` +
+		`<DBClusterIdentifier>test-cluster</DBClusterIdentifier><Engine>aurora-mysql</Engine><EngineVersion>5.7.mysql_aurora.2.11.2</EngineVersion>` +
+		`<DBClusterParameterGroup>default.aurora-mysql5.7</DBClusterParameterGroup>` +
+		`</DBCluster></DBClusters></DescribeDBClustersResult></DescribeDBClustersResponse>`,
+	"DescribeDBEngineVersions": `<DescribeDBEngineVersionsResponse><DescribeDBEngineVersionsResult><DBEngineVersions><DBEngineVersion>` +
+		`<DBParameterGroupFamily>aurora-mysql8.0</DBParameterGroupFamily><ValidUpgradeTarget>` +
+		`<UpgradeTarget><EngineVersion>8.0.35</EngineVersion></UpgradeTarget>` +
+		`<UpgradeTarget><EngineVersion>8.0.mysql_aurora.3.04.0</EngineVersion><IsMajorVersionUpgrade>true</IsMajorVersionUpgrade></UpgradeTarget>` +
+		`</ValidUpgradeTarget></DBEngineVersion></DBEngineVersions></DescribeDBEngineVersionsResult></DescribeDBEngineVersionsResponse>`,
+	"DescribeDBParameterGroups": `<DescribeDBParameterGroupsResponse><DescribeDBParameterGroupsResult><DBParameterGroups><DBParameterGroup>` +
+		`<DBParameterGroupName>custom-aurora-mysql57</DBParameterGroupName><DBParameterGroupFamily>aurora-mysql5.7</DBParameterGroupFamily>` +
+		`</DBParameterGroup></DBParameterGroups></DescribeDBParameterGroupsResult></DescribeDBParameterGroupsResponse>`,
+	"DescribeDBSnapshots": `<DescribeDBSnapshotsResponse><DescribeDBSnapshotsResult><DBSnapshots><DBSnapshot>` +
+		`<DBSnapshotIdentifier>test-instance-before-upgrade</DBSnapshotIdentifier><Status>available</Status>` +
+		`</DBSnapshot></DBSnapshots></DescribeDBSnapshotsResult></DescribeDBSnapshotsResponse>`,
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultWaitInterval = 30 * time.Second
+)
+
+// ErrTerminalStatus is matched by the error of WaitFor when the resource
+// reached a status it cannot leave by itself, such as failed or incompatible-*.
+var ErrTerminalStatus = errors.New("resource reached a terminal status")
+
+// WaitOptions configures WaitFor. The zero value polls every
+// DefaultWaitInterval until the context is done.
+type WaitOptions struct {
+	// Interval is the delay before the second poll. The first poll is immediate.
+	Interval time.Duration
+	// Backoff multiplies the interval after every poll when greater than 1.
+	Backoff float64
+	// MaxInterval caps the interval grown by Backoff.
+	MaxInterval time.Duration
+	// Timeout bounds the whole wait in addition to the context.
+	Timeout time.Duration
+	// OnProgress is called with the status observed by every poll.
+	OnProgress func(status string, elapsed time.Duration)
+}
+
+// StatusError is returned by WaitFor when the resource reached a terminal status.
+type StatusError struct {
+	Identifier string
+	Status     string
+	Desired    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s reached terminal status %s while waiting for %s", e.Identifier, e.Status, e.Desired)
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrTerminalStatus
+}
+
+// waitFor polls until poll returns desired, a terminal status or an error.
+func waitFor(ctx context.Context, id, desired string, opts *WaitOptions, poll func(context.Context) (string, error), terminal func(string) bool) error {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+
+	start := time.Now()
+	for {
+		status, err := poll(ctx)
+		if err != nil && !IsRetryable(err) {
+			return err
+		}
+		if err == nil {
+			if opts.OnProgress != nil {
+				opts.OnProgress(status, time.Since(start))
+			}
+			if status == desired {
+				return nil
+			}
+			if terminal(status) {
+				return &StatusError{Identifier: id, Status: status, Desired: desired}
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for %s to be %s: %w", id, desired, ctx.Err())
+		case <-timer.C:
+		}
+
+		if opts.Backoff > 1 {
+			interval = time.Duration(float64(interval) * opts.Backoff)
+			if opts.MaxInterval > 0 && interval > opts.MaxInterval {
+				interval = opts.MaxInterval
+			}
+		}
+	}
+}
+
+func isTerminalInstanceStatus(status string) bool {
+	switch DBInstanceStatus(status) {
+	case DBInstanceStatusFailed,
+		DBInstanceStatusRestoreError,
+		DBInstanceStatusInaccessibleEncryptionCredentials:
+		return true
+	}
+	return strings.HasPrefix(status, "incompatible-")
+}
+
+func isTerminalClusterStatus(status string) bool {
+	switch DBClusterStatus(status) {
+	case DBClusterStatusFailed,
+		DBClusterStatusCloningFailed,
+		DBClusterStatusMigrationFailed,
+		DBClusterStatusInaccessibleEncryptionCredentials:
+		return true
+	}
+	return strings.HasPrefix(status, "incompatible-")
+}
+
+// WaitFor waits until the instance reaches status, or is deleted when status
+// is DBInstanceStatusDeleted.
+func (s *rdsInstance) WaitFor(ctx context.Context, status DBInstanceStatus, opts *WaitOptions) error {
+	id := s.describeInstanceParam.DBInstanceIdentifier
+	if id == nil {
+		return errors.New("db instance identifier is required")
+	}
+	return waitFor(ctx, *id, string(status), opts, func(ctx context.Context) (string, error) {
+		desc, err := s.Describe(ctx)
+		if err != nil {
+			return "", err
+		}
+		if desc == nil || desc.DBInstanceIdentifier == "" {
+			return string(DBInstanceStatusDeleted), nil
+		}
+		return string(desc.DBInstanceStatus), nil
+	}, isTerminalInstanceStatus)
+}
+
+// WaitFor waits until the cluster reaches status, or is deleted when status
+// is DBClusterStatusDeleted.
+func (s *rdsCluster) WaitFor(ctx context.Context, status DBClusterStatus, opts *WaitOptions) error {
+	id := s.describeClusterParam.DBClusterIdentifier
+	if id == nil {
+		return errors.New("db cluster identifier is required")
+	}
+	return waitFor(ctx, *id, string(status), opts, func(ctx context.Context) (string, error) {
+		desc, err := s.Describe(ctx)
+		if err != nil {
+			return "", err
+		}
+		if desc == nil || desc.DBClusterIdentifier == "" {
+			return string(DBClusterStatusDeleted), nil
+		}
+		return desc.Status, nil
+	}, isTerminalClusterStatus)
+}
+
+// WaitFor waits until the cluster reaches status, or is deleted when status
+// is DBClusterStatusDeleted. Waiting for DBClusterStatusAvailable also waits
+// for every instance of the cluster to be available.
+func (s *rdsAurora) WaitFor(ctx context.Context, status DBClusterStatus, opts *WaitOptions) error {
+	id := s.describeClusterParam.DBClusterIdentifier
+	if id == nil {
+		return errors.New("db cluster identifier is required")
+	}
+	return waitFor(ctx, *id, string(status), opts, func(ctx context.Context) (string, error) {
+		desc, err := s.Describe(ctx)
+		if err != nil {
+			return "", err
+		}
+		if desc == nil || desc.DBClusterIdentifier == "" {
+			return string(DBClusterStatusDeleted), nil
+		}
+		if DBClusterStatus(desc.Status) != DBClusterStatusAvailable || status != DBClusterStatusAvailable {
+			return desc.Status, nil
+		}
+
+		instances, err := s.describeInstances(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, ins := range instances {
+			if ins.DBInstanceStatus != DBInstanceStatusAvailable {
+				if isTerminalInstanceStatus(string(ins.DBInstanceStatus)) {
+					return string(ins.DBInstanceStatus), nil
+				}
+				return fmt.Sprintf("instance %s %s", ins.DBInstanceIdentifier, ins.DBInstanceStatus), nil
+			}
+		}
+		return desc.Status, nil
+	}, func(status string) bool {
+		return isTerminalClusterStatus(status) || isTerminalInstanceStatus(status)
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func statusSequence(statuses ...string) func(context.Context) (string, error) {
+	i := 0
+	return func(context.Context) (string, error) {
+		status := statuses[i]
+		if i < len(statuses)-1 {
+			i++
+		}
+		return status, nil
+	}
+}
+
+var _ = Describe("Waiter", func() {
+	It("should wait until the desired status and report progress", func() {
+		var seen []string
+		opts := &WaitOptions{
+			Interval: time.Millisecond,
+			Backoff:  2,
+			OnProgress: func(status string, _ time.Duration) {
+				seen = append(seen, status)
+			},
+		}
+		poll := statusSequence("creating", "backing-up", "available")
+		Expect(waitFor(context.Background(), "foo", "available", opts, poll, isTerminalInstanceStatus)).To(Succeed())
+		Expect(seen).To(Equal([]string{"creating", "backing-up", "available"}))
+	})
+
+	It("should stop on a terminal status", func() {
+		poll := statusSequence("creating", "incompatible-parameters")
+		err := waitFor(context.Background(), "foo", "available", &WaitOptions{Interval: time.Millisecond}, poll, isTerminalInstanceStatus)
+		Expect(errors.Is(err, ErrTerminalStatus)).To(BeTrue())
+
+		var se *StatusError
+		Expect(errors.As(err, &se)).To(BeTrue())
+		Expect(se.Status).To(Equal("incompatible-parameters"))
+	})
+
+	It("should time out", func() {
+		poll := statusSequence("modifying")
+		err := waitFor(context.Background(), "foo", "available", &WaitOptions{Interval: time.Millisecond, Timeout: 10 * time.Millisecond}, poll, isTerminalClusterStatus)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+
+	It("should keep polling on retryable errors", func() {
+		calls := 0
+		poll := func(context.Context) (string, error) {
+			calls++
+			if calls == 1 {
+				return "", &Error{Operation: "DescribeDBClusters", Code: "Throttling", Kind: ErrThrottled}
+			}
+			return string(DBClusterStatusDeleted), nil
+		}
+		Expect(waitFor(context.Background(), "foo", string(DBClusterStatusDeleted), &WaitOptions{Interval: time.Millisecond}, poll, isTerminalClusterStatus)).To(Succeed())
+		Expect(calls).To(Equal(2))
+	})
+})
@@ -0,0 +1,262 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+const (
+	DefaultWatchInterval = 30 * time.Second
+)
+
+type StatusChange string
+
+const (
+	StatusChangeStatus   StatusChange = "status"
+	StatusChangeEndpoint StatusChange = "endpoint"
+	StatusChangeMembers  StatusChange = "members"
+)
+
+// StatusEvent is sent by Watch for the first observed state and then every
+// time the status, an endpoint or the cluster members change.
+type StatusEvent struct {
+	Identifier string
+	Time       time.Time
+	// Status is a DBInstanceStatus for instances and a DBClusterStatus for
+	// clusters, DBInstanceStatusDeleted or DBClusterStatusDeleted once gone.
+	Status         string
+	Endpoint       Endpoint
+	ReaderEndpoint Endpoint
+	Members        []ClusterMember
+	// Changes lists what changed since the previous event, it is empty for the first one.
+	Changes []StatusChange
+	// Err is set on the last event when polling failed with an error which is
+	// not retryable.
+	Err error
+}
+
+// watch polls every interval and sends the events which differ from the
+// previous one. The channel is closed when ctx is done, after a deleted
+// status or after an event carrying an error.
+func watch(ctx context.Context, id string, interval time.Duration, deleted string, poll func(context.Context) (*StatusEvent, error)) <-chan StatusEvent {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ch := make(chan StatusEvent)
+
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var prev *StatusEvent
+		for {
+			ev, err := poll(ctx)
+			switch {
+			case err != nil && ctx.Err() != nil:
+				return
+			case err != nil && !IsRetryable(err):
+				send(ctx, ch, StatusEvent{Identifier: id, Time: time.Now(), Err: err})
+				return
+			case err == nil:
+				ev.Identifier = id
+				ev.Time = time.Now()
+				if prev != nil {
+					ev.Changes = diffStatusEvent(prev, ev)
+				}
+				if prev == nil || len(ev.Changes) > 0 {
+					if !send(ctx, ch, *ev) {
+						return
+					}
+				}
+				if ev.Status == deleted {
+					return
+				}
+				prev = ev
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ch
+}
+
+func send(ctx context.Context, ch chan<- StatusEvent, ev StatusEvent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case ch <- ev:
+		return true
+	}
+}
+
+func diffStatusEvent(prev, cur *StatusEvent) []StatusChange {
+	var changes []StatusChange
+	if prev.Status != cur.Status {
+		changes = append(changes, StatusChangeStatus)
+	}
+	if prev.Endpoint != cur.Endpoint || prev.ReaderEndpoint != cur.ReaderEndpoint {
+		changes = append(changes, StatusChangeEndpoint)
+	}
+	if !sameMembers(prev.Members, cur.Members) {
+		changes = append(changes, StatusChangeMembers)
+	}
+	return changes
+}
+
+// sameMembers compares the identifiers and writer flags of the members, in any order.
+func sameMembers(a, b []ClusterMember) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	writers := map[string]bool{}
+	for _, m := range a {
+		writers[m.DBInstanceIdentifier] = m.IsClusterWrite
+	}
+	for _, m := range b {
+		w, ok := writers[m.DBInstanceIdentifier]
+		if !ok || w != m.IsClusterWrite {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedMembers(members []ClusterMember) []ClusterMember {
+	out := append([]ClusterMember(nil), members...)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].DBInstanceIdentifier < out[j].DBInstanceIdentifier
+	})
+	return out
+}
+
+// cloneFilters copies filters, which setFilter updates in place, so that a
+// watch polls with the filters of its start.
+func cloneFilters(filters []types.Filter) []types.Filter {
+	if filters == nil {
+		return nil
+	}
+	clone := make([]types.Filter, len(filters))
+	for i, f := range filters {
+		clone[i] = types.Filter{Name: f.Name, Values: append([]string(nil), f.Values...)}
+	}
+	return clone
+}
+
+func closedWithError(err error) <-chan StatusEvent {
+	ch := make(chan StatusEvent, 1)
+	ch <- StatusEvent{Time: time.Now(), Err: err}
+	close(ch)
+	return ch
+}
+
+func (s *rdsInstance) SetWatchInterval(interval time.Duration) Instance {
+	s.watchInterval = interval
+	return s
+}
+
+// Watch polls the instance and sends an event every time its status or endpoint changes.
+func (s *rdsInstance) Watch(ctx context.Context) <-chan StatusEvent {
+	id := s.describeInstanceParam.DBInstanceIdentifier
+	if id == nil {
+		return closedWithError(errors.New("db instance identifier is required"))
+	}
+	param := *s.describeInstanceParam
+	param.Filters = cloneFilters(param.Filters)
+	watched := &rdsInstance{core: s.core, describeInstanceParam: &param}
+	return watch(ctx, *id, s.watchInterval, string(DBInstanceStatusDeleted), func(ctx context.Context) (*StatusEvent, error) {
+		desc, err := watched.Describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil || desc.DBInstanceIdentifier == "" {
+			return &StatusEvent{Status: string(DBInstanceStatusDeleted)}, nil
+		}
+		return &StatusEvent{
+			Status:   string(desc.DBInstanceStatus),
+			Endpoint: desc.Endpoint,
+		}, nil
+	})
+}
+
+func clusterStatusEvent(desc *DescCluster) *StatusEvent {
+	if desc == nil || desc.DBClusterIdentifier == "" {
+		return &StatusEvent{Status: string(DBClusterStatusDeleted)}
+	}
+	return &StatusEvent{
+		Status:         desc.Status,
+		Endpoint:       Endpoint{Address: desc.PrimaryEndpoint, Port: desc.Port},
+		ReaderEndpoint: Endpoint{Address: desc.ReaderEndpoint, Port: desc.Port},
+		Members:        sortedMembers(desc.DBClusterMembers),
+	}
+}
+
+func (s *rdsCluster) SetWatchInterval(interval time.Duration) Cluster {
+	s.watchInterval = interval
+	return s
+}
+
+// Watch polls the cluster and sends an event every time its status, its
+// endpoints or the writer flag of its members change.
+func (s *rdsCluster) Watch(ctx context.Context) <-chan StatusEvent {
+	id := s.describeClusterParam.DBClusterIdentifier
+	if id == nil {
+		return closedWithError(errors.New("db cluster identifier is required"))
+	}
+	param := *s.describeClusterParam
+	param.Filters = cloneFilters(param.Filters)
+	watched := &rdsCluster{core: s.core, describeClusterParam: &param}
+	return watch(ctx, *id, s.watchInterval, string(DBClusterStatusDeleted), func(ctx context.Context) (*StatusEvent, error) {
+		desc, err := watched.Describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return clusterStatusEvent(desc), nil
+	})
+}
+
+func (s *rdsAurora) SetWatchInterval(interval time.Duration) Aurora {
+	s.watchInterval = interval
+	return s
+}
+
+// Watch polls the cluster and sends an event every time its status, its
+// endpoints or the writer flag of its members change.
+func (s *rdsAurora) Watch(ctx context.Context) <-chan StatusEvent {
+	id := s.describeClusterParam.DBClusterIdentifier
+	if id == nil {
+		return closedWithError(errors.New("db cluster identifier is required"))
+	}
+	param := *s.describeClusterParam
+	param.Filters = cloneFilters(param.Filters)
+	watched := &rdsAurora{core: s.core, describeClusterParam: &param}
+	return watch(ctx, *id, s.watchInterval, string(DBClusterStatusDeleted), func(ctx context.Context) (*StatusEvent, error) {
+		desc, err := watched.Describe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return clusterStatusEvent(desc), nil
+	})
+}
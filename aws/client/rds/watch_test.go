@@ -0,0 +1,105 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watch", func() {
+	It("should send an event for every change until deleted", func() {
+		descs := []*DescCluster{
+			{DBClusterIdentifier: "foo", Status: "creating"},
+			{DBClusterIdentifier: "foo", Status: "creating"},
+			{DBClusterIdentifier: "foo", Status: "available", PrimaryEndpoint: "foo.rds", Port: 3306,
+				DBClusterMembers: []ClusterMember{{DBInstanceIdentifier: "a", IsClusterWrite: true}, {DBInstanceIdentifier: "b"}}},
+			{DBClusterIdentifier: "foo", Status: "available", PrimaryEndpoint: "foo.rds", Port: 3306,
+				DBClusterMembers: []ClusterMember{{DBInstanceIdentifier: "b", IsClusterWrite: true}, {DBInstanceIdentifier: "a"}}},
+			nil,
+		}
+		i := 0
+		poll := func(context.Context) (*StatusEvent, error) {
+			desc := descs[i]
+			i++
+			return clusterStatusEvent(desc), nil
+		}
+
+		var events []StatusEvent
+		for ev := range watch(context.Background(), "foo", time.Millisecond, string(DBClusterStatusDeleted), poll) {
+			events = append(events, ev)
+		}
+
+		Expect(events).To(HaveLen(4))
+		Expect(events[0].Status).To(Equal("creating"))
+		Expect(events[0].Changes).To(BeEmpty())
+		Expect(events[1].Changes).To(ConsistOf(StatusChangeStatus, StatusChangeEndpoint, StatusChangeMembers))
+		Expect(events[2].Changes).To(ConsistOf(StatusChangeMembers))
+		Expect(events[3].Status).To(Equal(string(DBClusterStatusDeleted)))
+	})
+
+	It("should close the channel when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		poll := func(context.Context) (*StatusEvent, error) {
+			return &StatusEvent{Status: "available"}, nil
+		}
+		ch := watch(ctx, "foo", time.Millisecond, string(DBInstanceStatusDeleted), poll)
+		Expect((<-ch).Status).To(Equal("available"))
+		cancel()
+		Eventually(ch).Should(BeClosed())
+	})
+
+	It("should keep watching the instance of its start after the builder changes", func() {
+		var (
+			mu  sync.Mutex
+			ids []string
+		)
+		core := serveClient(func(req *http.Request) (int, string) {
+			body, _ := io.ReadAll(req.Body)
+			form, _ := url.ParseQuery(string(body))
+			mu.Lock()
+			ids = append(ids, form.Get("DBInstanceIdentifier"))
+			mu.Unlock()
+			return http.StatusOK, `<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances><DBInstance>` +
+				`<DBInstanceIdentifier>foo</DBInstanceIdentifier><DBInstanceStatus>available</DBInstanceStatus>` +
+				`</DBInstance></DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ins := newInstance(core)
+		ch := ins.SetDBInstanceIdentifier("foo").SetWatchInterval(time.Millisecond).Watch(ctx)
+		Expect((<-ch).Status).To(Equal("available"))
+		ins.SetDBInstanceIdentifier("bar")
+		Eventually(func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(ids)
+		}).Should(BeNumerically(">=", 3))
+		cancel()
+		Eventually(ch).Should(BeClosed())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(ids).To(HaveEach("foo"))
+	})
+})
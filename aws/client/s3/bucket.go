@@ -30,11 +30,15 @@ type Bucket interface {
 	SetBucket(bucket string) Bucket
 	SetUploadBodyReader(reader io.Reader) Bucket
 	SetBucketLocationConstraint(location string) Bucket
+	SetTags(tags map[string]string) Bucket
 
 	Create(context.Context) error
 	List(ctx context.Context) ([]*DescBucket, error)
 	Delete(context.Context) error
 	UploadPart(context.Context) error
+	PutTagging(context.Context) error
+	GetTagging(context.Context) (map[string]string, error)
+	DeleteTagging(context.Context) error
 }
 
 type bucket struct {
@@ -42,6 +46,17 @@ type bucket struct {
 	createBucketParam *s3.CreateBucketInput
 	deleteBucketParam *s3.DeleteBucketInput
 	uploadPartParam   *s3.UploadPartInput
+
+	tagSet []types.Tag
+}
+
+func newBucket(core *s3.Client) *bucket {
+	return &bucket{
+		core:              core,
+		createBucketParam: &s3.CreateBucketInput{},
+		deleteBucketParam: &s3.DeleteBucketInput{},
+		uploadPartParam:   &s3.UploadPartInput{},
+	}
 }
 
 func (s *bucket) SetBucket(bucket string) Bucket {
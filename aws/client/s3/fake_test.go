@@ -0,0 +1,48 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	sdks3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/database-mesh/golang-sdk/aws/client/s3"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// fakeService returns a service whose calls are answered by serve, with the
+// status and the xml body it returns, without reaching the network.
+func fakeService(serve func(req *http.Request) (int, string)) s3.S3 {
+	return s3.NewService(awssdk.Config{Region: "us-east-1", Credentials: awssdk.AnonymousCredentials{}}, func(o *sdks3.Options) {
+		o.Retryer = awssdk.NopRetryer{}
+		o.HTTPClient = doerFunc(func(req *http.Request) (*http.Response, error) {
+			status, body := serve(req)
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Content-Type": []string{"application/xml"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		})
+	})
+}
@@ -24,6 +24,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
+// maxDeleteObjects is the number of keys DeleteObjects accepts at once.
+const maxDeleteObjects = 1000
+
 type Object interface {
 	SetBucket(bucket string) Object
 	SetKey(key string) Object
@@ -32,6 +35,7 @@ type Object interface {
 	SetReader(reader io.Reader) Object
 	SetPrefix(prefix string) Object
 	SetFolderName(folderName string) Object
+	SetTags(tags map[string]string) Object
 
 	Put(context.Context) error
 	Get(context.Context) (string, error)
@@ -39,17 +43,32 @@ type Object interface {
 	Delete(context.Context) error
 	Head(context.Context) error
 	DeleteFolder(context.Context) error
+	PutTagging(context.Context) error
+	GetTagging(context.Context) (map[string]string, error)
+	DeleteTagging(context.Context) error
 }
 
 type object struct {
 	core              *s3.Client
 	putObjectParam    *s3.PutObjectInput
 	getObjectParam    *s3.GetObjectInput
-	listObjectsParam  *s3.ListObjectsInput
+	listObjectsParam  *s3.ListObjectsV2Input
 	deleteObjectParam *s3.DeleteObjectInput
 	headObjectParam   *s3.HeadObjectInput
 
 	folderName string
+	tagSet     []types.Tag
+}
+
+func newObject(core *s3.Client) *object {
+	return &object{
+		core:              core,
+		putObjectParam:    &s3.PutObjectInput{},
+		getObjectParam:    &s3.GetObjectInput{},
+		listObjectsParam:  &s3.ListObjectsV2Input{},
+		deleteObjectParam: &s3.DeleteObjectInput{},
+		headObjectParam:   &s3.HeadObjectInput{},
+	}
 }
 
 func (s *object) SetBucket(bucket string) Object {
@@ -120,20 +139,27 @@ func (s *object) Get(ctx context.Context) (string, error) {
 }
 
 func (s *object) List(ctx context.Context) (fileNames []string, err error) {
-	objs, err := s.core.ListObjects(ctx, s.listObjectsParam)
-	if err != nil {
-		return nil, err
-	}
+	return s.list(ctx, s.listObjectsParam)
+}
 
-	prefix := aws.ToString(objs.Prefix)
-	for _, obj := range objs.Contents {
-		if *obj.Key == prefix {
-			continue
+// list returns the keys under the prefix of param, following the
+// continuation tokens past the 1000 keys of a page.
+func (s *object) list(ctx context.Context, param *s3.ListObjectsV2Input) (fileNames []string, err error) {
+	prefix := aws.ToString(param.Prefix)
+	paginator := s3.NewListObjectsV2Paginator(s.core, param)
+	for paginator.HasMorePages() {
+		objs, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs.Contents {
+			if aws.ToString(obj.Key) == prefix {
+				continue
+			}
+			fileNames = append(fileNames, aws.ToString(obj.Key))
 		}
-		fileNames = append(fileNames, *obj.Key)
 	}
-
-	return fileNames, err
+	return fileNames, nil
 }
 
 func (s *object) Delete(ctx context.Context) error {
@@ -147,27 +173,34 @@ func (s *object) Head(ctx context.Context) error {
 }
 
 func (s *object) DeleteFolder(ctx context.Context) error {
-	s.SetPrefix(s.folderName)
-	fileNames, err := s.List(ctx)
+	param := *s.listObjectsParam
+	param.Prefix = aws.String(s.folderName)
+	fileNames, err := s.list(ctx, &param)
 	if err != nil {
 		return err
 	}
-	if len(fileNames) == 0 {
-		return nil
-	}
 
-	objs := make([]types.ObjectIdentifier, len(fileNames))
-	for i, fileName := range fileNames {
-		objs[i] = types.ObjectIdentifier{
-			Key: aws.String(fileName),
+	for start := 0; start < len(fileNames); start += maxDeleteObjects {
+		end := start + maxDeleteObjects
+		if end > len(fileNames) {
+			end = len(fileNames)
+		}
+		objs := make([]types.ObjectIdentifier, 0, end-start)
+		for _, fileName := range fileNames[start:end] {
+			objs = append(objs, types.ObjectIdentifier{
+				Key: aws.String(fileName),
+			})
 		}
-	}
 
-	_, err = s.core.DeleteObjects(ctx, &s3.DeleteObjectsInput{
-		Bucket: s.deleteObjectParam.Bucket,
-		Delete: &types.Delete{
-			Objects: objs,
-		},
-	})
-	return err
+		_, err = s.core.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: s.deleteObjectParam.Bucket,
+			Delete: &types.Delete{
+				Objects: objs,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -18,6 +18,8 @@ package s3_test
 
 import (
 	"fmt"
+	"net/http"
+
 	"github.com/database-mesh/golang-sdk/aws"
 	"github.com/database-mesh/golang-sdk/aws/client/s3"
 
@@ -76,3 +78,23 @@ var _ = Describe("Object", func() {
 		})
 	})
 })
+
+var _ = Describe("Object listing", func() {
+	It("should follow the continuation tokens past the first page", func() {
+		var tokens []string
+		svc := fakeService(func(req *http.Request) (int, string) {
+			token := req.URL.Query().Get("continuation-token")
+			tokens = append(tokens, token)
+			if token == "" {
+				return http.StatusOK, `<ListBucketResult><Name>exports</Name><Prefix>export-1/</Prefix><IsTruncated>true</IsTruncated><NextContinuationToken>page-2</NextContinuationToken>` +
+					`<Contents><Key>export-1/</Key></Contents><Contents><Key>export-1/export_info_export-1.json</Key></Contents></ListBucketResult>`
+			}
+			return http.StatusOK, `<ListBucketResult><Name>exports</Name><Prefix>export-1/</Prefix><IsTruncated>false</IsTruncated>` +
+				`<Contents><Key>export-1/db/table/1/part-00000.gz.parquet</Key></Contents></ListBucketResult>`
+		})
+		keys, err := svc.Object().SetBucket("exports").SetPrefix("export-1").List(ctx)
+		Expect(err).To(BeNil())
+		Expect(keys).To(Equal([]string{"export-1/export_info_export-1.json", "export-1/db/table/1/part-00000.gz.parquet"}))
+		Expect(tokens).To(Equal([]string{"", "page-2"}))
+	})
+})
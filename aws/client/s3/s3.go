@@ -19,39 +19,38 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+// S3 creates the builders of the s3 resources. Every call returns a new
+// builder, so builders are never shared between callers. A single builder is
+// not safe for concurrent use, and operations never modify the builder they
+// are called on.
 type S3 interface {
 	Object() Object
 	Bucket() Bucket
 }
 
 type service struct {
-	object *object
-	bucket *bucket
+	core *s3.Client
 }
 
 func (s *service) Object() Object {
-	return s.object
+	return newObject(s.core)
 }
 
 func (s *service) Bucket() Bucket {
-	return s.bucket
+	return newBucket(s.core)
 }
 
-func NewService(sess aws.Config) *service {
+// WithPathStyle addresses buckets as https://host/bucket instead of
+// https://bucket.host, as required by most S3 stand-ins like MinIO.
+func WithPathStyle(enable bool) func(*s3.Options) {
+	return func(o *s3.Options) {
+		o.UsePathStyle = enable
+	}
+}
+
+// NewService returns an S3 whose builders share one goroutine-safe client.
+func NewService(sess aws.Config, optFns ...func(*s3.Options)) *service {
 	return &service{
-		bucket: &bucket{
-			core:              s3.NewFromConfig(sess),
-			createBucketParam: &s3.CreateBucketInput{},
-			deleteBucketParam: &s3.DeleteBucketInput{},
-			uploadPartParam:   &s3.UploadPartInput{},
-		},
-		object: &object{
-			core:              s3.NewFromConfig(sess),
-			putObjectParam:    &s3.PutObjectInput{},
-			getObjectParam:    &s3.GetObjectInput{},
-			listObjectsParam:  &s3.ListObjectsInput{},
-			deleteObjectParam: &s3.DeleteObjectInput{},
-			headObjectParam:   &s3.HeadObjectInput{},
-		},
+		core: s3.NewFromConfig(sess, optFns...),
 	}
 }
@@ -0,0 +1,132 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// SetTags sets the tags of the object written by Put and by PutTagging.
+func (s *object) SetTags(tags map[string]string) Object {
+	s.tagSet = toTagSet(tags)
+	s.putObjectParam.Tagging = aws.String(encodeTagging(tags))
+	return s
+}
+
+// PutTagging replaces the tags of the object with the ones set by SetTags.
+func (s *object) PutTagging(ctx context.Context) error {
+	_, err := s.core.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  s.getObjectParam.Bucket,
+		Key:     s.getObjectParam.Key,
+		Tagging: &types.Tagging{TagSet: s.tagSet},
+	})
+	return err
+}
+
+func (s *object) GetTagging(ctx context.Context) (map[string]string, error) {
+	out, err := s.core.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: s.getObjectParam.Bucket,
+		Key:    s.getObjectParam.Key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertTagSet(out.TagSet), nil
+}
+
+func (s *object) DeleteTagging(ctx context.Context) error {
+	_, err := s.core.DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{
+		Bucket: s.getObjectParam.Bucket,
+		Key:    s.getObjectParam.Key,
+	})
+	return err
+}
+
+// SetTags sets the tags of the bucket written by PutTagging.
+func (s *bucket) SetTags(tags map[string]string) Bucket {
+	s.tagSet = toTagSet(tags)
+	return s
+}
+
+// PutTagging replaces the tags of the bucket with the ones set by SetTags.
+func (s *bucket) PutTagging(ctx context.Context) error {
+	_, err := s.core.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  s.createBucketParam.Bucket,
+		Tagging: &types.Tagging{TagSet: s.tagSet},
+	})
+	return err
+}
+
+// GetTagging returns the tags of the bucket, empty when it has none.
+func (s *bucket) GetTagging(ctx context.Context) (map[string]string, error) {
+	out, err := s.core.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: s.createBucketParam.Bucket,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	return convertTagSet(out.TagSet), nil
+}
+
+func (s *bucket) DeleteTagging(ctx context.Context) error {
+	_, err := s.core.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{
+		Bucket: s.createBucketParam.Bucket,
+	})
+	return err
+}
+
+// toTagSet converts tags to s3 tags sorted by key.
+func toTagSet(tags map[string]string) []types.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]types.Tag, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return out
+}
+
+// encodeTagging encodes tags as the query string of the x-amz-tagging header.
+func encodeTagging(tags map[string]string) string {
+	values := url.Values{}
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+func convertTagSet(in []types.Tag) map[string]string {
+	out := make(map[string]string, len(in))
+	for _, t := range in {
+		out[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return out
+}
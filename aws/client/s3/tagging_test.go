@@ -0,0 +1,71 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tagging", func() {
+	It("should encode the object tags into the tagging header of Put", func() {
+		var header string
+		svc := fakeService(func(req *http.Request) (int, string) {
+			header = req.Header.Get("X-Amz-Tagging")
+			return http.StatusOK, ""
+		})
+		err := svc.Object().SetBucket("backups").SetKey("shop/2023-05-01.sql").
+			SetTags(map[string]string{"owner": "dba team", "retention": "30d&more"}).SetValue("dump").Put(ctx)
+		Expect(err).To(BeNil())
+		values, err := url.ParseQuery(header)
+		Expect(err).To(BeNil())
+		Expect(values).To(Equal(url.Values{"owner": {"dba team"}, "retention": {"30d&more"}}))
+	})
+
+	It("should put the object and bucket tags sorted by key", func() {
+		var bodies []string
+		svc := fakeService(func(req *http.Request) (int, string) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			return http.StatusOK, ""
+		})
+		tags := map[string]string{"owner": "dba", "env": "prod"}
+		Expect(svc.Object().SetBucket("backups").SetKey("shop.sql").SetTags(tags).PutTagging(ctx)).To(BeNil())
+		Expect(svc.Bucket().SetBucket("backups").SetTags(tags).PutTagging(ctx)).To(BeNil())
+		for _, body := range bodies {
+			Expect(body).To(MatchRegexp(`<Key>env</Key><Value>prod</Value></Tag><Tag><Key>owner</Key><Value>dba</Value>`))
+		}
+	})
+
+	It("should read the tags and an empty bucket tag set", func() {
+		svc := fakeService(func(req *http.Request) (int, string) {
+			if req.URL.Path == "/" || req.URL.Query().Has("versionId") {
+				return http.StatusNotFound, `<Error><Code>NoSuchTagSet</Code><Message>The TagSet does not exist</Message></Error>`
+			}
+			return http.StatusOK, `<Tagging><TagSet><Tag><Key>owner</Key><Value>dba</Value></Tag></TagSet></Tagging>`
+		})
+		tags, err := svc.Object().SetBucket("backups").SetKey("shop.sql").GetTagging(ctx)
+		Expect(err).To(BeNil())
+		Expect(tags).To(Equal(map[string]string{"owner": "dba"}))
+
+		tags, err = svc.Bucket().SetBucket("backups").GetTagging(ctx)
+		Expect(err).To(BeNil())
+		Expect(tags).To(BeEmpty())
+	})
+})
@@ -0,0 +1,148 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	EnvRoleArn              = "AWS_ROLE_ARN"
+	EnvWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	EnvRoleSessionName      = "AWS_ROLE_SESSION_NAME"
+
+	DefaultRoleSessionName = "database-mesh-golang-sdk"
+)
+
+type credentialSource string
+
+const (
+	credentialSourceStatic      credentialSource = "static"
+	credentialSourceEnv         credentialSource = "env"
+	credentialSourceProfile     credentialSource = "profile"
+	credentialSourceWebIdentity credentialSource = "web-identity"
+	credentialSourceAssumeRole  credentialSource = "assume-role"
+	credentialSourceDefault     credentialSource = "default"
+)
+
+// AssumeRole describes an STS AssumeRole call.
+type AssumeRole struct {
+	RoleArn         string
+	RoleSessionName string
+	// ExternalID is required by roles whose trust policy sets sts:ExternalId.
+	ExternalID string
+	// Duration defaults to 15 minutes when zero.
+	Duration time.Duration
+}
+
+type credential struct {
+	region string
+	source credentialSource
+
+	accessKeyId     string
+	secretAccessKey string
+
+	profile string
+
+	roleArn         string
+	tokenFile       string
+	roleSessionName string
+
+	assumeRole AssumeRole
+}
+
+func (c credential) staticProvider() (aws.CredentialsProvider, error) {
+	if c.source != credentialSourceEnv {
+		return credentials.NewStaticCredentialsProvider(c.accessKeyId, c.secretAccessKey, ""), nil
+	}
+
+	env, err := awscfg.NewEnvConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !env.Credentials.HasKeys() {
+		return nil, fmt.Errorf("no credentials found in environment")
+	}
+	return credentials.NewStaticCredentialsProvider(
+		env.Credentials.AccessKeyID,
+		env.Credentials.SecretAccessKey,
+		env.Credentials.SessionToken,
+	), nil
+}
+
+// checkProfile loads profile from the shared config and credentials files,
+// which LoadDefaultConfig silently skips when the profile does not exist.
+func checkProfile(ctx context.Context, profile string) error {
+	env, err := awscfg.NewEnvConfig()
+	if err != nil {
+		return err
+	}
+	_, err = awscfg.LoadSharedConfigProfile(ctx, profile, func(o *awscfg.LoadSharedConfigOptions) {
+		if env.SharedConfigFile != "" {
+			o.ConfigFiles = []string{env.SharedConfigFile}
+		}
+		if env.SharedCredentialsFile != "" {
+			o.CredentialsFiles = []string{env.SharedCredentialsFile}
+		}
+	})
+	return err
+}
+
+func newAssumeRoleProvider(cfg aws.Config, role AssumeRole) aws.CredentialsProvider {
+	return stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = role.RoleSessionName
+		if o.RoleSessionName == "" {
+			o.RoleSessionName = DefaultRoleSessionName
+		}
+		if role.ExternalID != "" {
+			o.ExternalID = aws.String(role.ExternalID)
+		}
+		if role.Duration > 0 {
+			o.Duration = role.Duration
+		}
+	})
+}
+
+func newWebIdentityProvider(cfg aws.Config, c credential) (aws.CredentialsProvider, error) {
+	roleArn, tokenFile, sessionName := c.roleArn, c.tokenFile, c.roleSessionName
+	if roleArn == "" {
+		roleArn = os.Getenv(EnvRoleArn)
+	}
+	if tokenFile == "" {
+		tokenFile = os.Getenv(EnvWebIdentityTokenFile)
+	}
+	if sessionName == "" {
+		sessionName = os.Getenv(EnvRoleSessionName)
+	}
+	if sessionName == "" {
+		sessionName = DefaultRoleSessionName
+	}
+	if roleArn == "" || tokenFile == "" {
+		return nil, fmt.Errorf("web identity requires role arn and token file")
+	}
+
+	return stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), roleArn, stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+		o.RoleSessionName = sessionName
+	}), nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegionError is the failure to build or validate the session of one region.
+// Account is set when the session belongs to a Registry.
+type RegionError struct {
+	Account string
+	Region  string
+	Err     error
+}
+
+func (e *RegionError) Error() string {
+	if e.Account != "" {
+		return fmt.Sprintf("account %s region %s: %s", e.Account, e.Region, e.Err)
+	}
+	return fmt.Sprintf("region %s: %s", e.Region, e.Err)
+}
+
+func (e *RegionError) Unwrap() error {
+	return e.Err
+}
+
+// BuildError aggregates every RegionError of a BuildE call.
+type BuildError struct {
+	Errors []*RegionError
+}
+
+func (e *BuildError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("failed to build %d aws session(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+func (e *BuildError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Regions returns the regions which failed.
+func (e *BuildError) Regions() []string {
+	regions := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		regions = append(regions, err.Region)
+	}
+	return regions
+}
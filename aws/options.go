@@ -0,0 +1,204 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awscfg "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// AllServices makes SetEndpoint apply to every service without an endpoint of its own.
+const AllServices = ""
+
+type sessionOptions struct {
+	// endpoints is keyed by service id, such as rds.ServiceID or s3.ServiceID
+	endpoints map[string]string
+
+	retryMode        aws.RetryMode
+	retryMaxAttempts int
+	retryMaxBackoff  time.Duration
+
+	timeout      time.Duration
+	proxy        string
+	caBundle     []byte
+	caBundleFile string
+}
+
+// SetEndpoint sends the requests of service, or of every service when
+// service is AllServices, to url instead of the AWS endpoint. This is meant
+// for stand-ins like LocalStack, MinIO or moto.
+func (s *awsCreds) SetEndpoint(service, url string) *awsCreds {
+	if s.options.endpoints == nil {
+		s.options.endpoints = map[string]string{}
+	}
+	s.options.endpoints[service] = url
+	return s
+}
+
+// SetRetryMode sets the retry mode, aws.RetryModeStandard or aws.RetryModeAdaptive.
+func (s *awsCreds) SetRetryMode(mode aws.RetryMode) *awsCreds {
+	s.options.retryMode = mode
+	return s
+}
+
+// SetRetryMaxAttempts sets the maximum number of attempts of each call, including the first one.
+func (s *awsCreds) SetRetryMaxAttempts(attempts int) *awsCreds {
+	s.options.retryMaxAttempts = attempts
+	return s
+}
+
+// SetRetryMaxBackoff caps the exponential backoff delay between two attempts.
+func (s *awsCreds) SetRetryMaxBackoff(backoff time.Duration) *awsCreds {
+	s.options.retryMaxBackoff = backoff
+	return s
+}
+
+// SetTimeout sets the timeout of each HTTP request attempt, including reading
+// the response body. It does not bound the whole call: with retries, a call
+// may last up to the max attempts times timeout plus the backoff delays. Use
+// a context deadline to bound the whole call.
+func (s *awsCreds) SetTimeout(timeout time.Duration) *awsCreds {
+	s.options.timeout = timeout
+	return s
+}
+
+// SetProxy sends every request through the HTTP proxy at url.
+func (s *awsCreds) SetProxy(url string) *awsCreds {
+	s.options.proxy = url
+	return s
+}
+
+// SetCABundle trusts the PEM encoded certificates of bundle in addition to
+// the system roots, such as the CA of a TLS intercepting corporate proxy.
+func (s *awsCreds) SetCABundle(bundle []byte) *awsCreds {
+	s.options.caBundle = bundle
+	return s
+}
+
+// SetCABundleFile is SetCABundle with the content of path, read when the sessions are built.
+func (s *awsCreds) SetCABundleFile(path string) *awsCreds {
+	s.options.caBundleFile = path
+	return s
+}
+
+func (o sessionOptions) loadOptions() ([]func(*awscfg.LoadOptions) error, error) {
+	var opts []func(*awscfg.LoadOptions) error
+
+	if o.caBundleFile != "" {
+		bundle, err := os.ReadFile(o.caBundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca bundle: %w", err)
+		}
+		o.caBundle = append(append([]byte{}, o.caBundle...), bundle...)
+	}
+
+	if len(o.endpoints) > 0 {
+		opts = append(opts, awscfg.WithEndpointResolverWithOptions(o.endpointResolver()))
+	}
+
+	if o.retryMode != "" || o.retryMaxAttempts > 0 || o.retryMaxBackoff > 0 {
+		opts = append(opts, awscfg.WithRetryer(o.retryer))
+	}
+
+	if o.timeout > 0 || o.proxy != "" || len(o.caBundle) > 0 {
+		client := awshttp.NewBuildableClient()
+		if o.timeout > 0 {
+			client = client.WithTimeout(o.timeout)
+		}
+		if o.proxy != "" {
+			u, err := url.Parse(o.proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy url: %w", err)
+			}
+			client = client.WithTransportOptions(func(t *http.Transport) {
+				t.Proxy = http.ProxyURL(u)
+			})
+		}
+		if len(o.caBundle) > 0 {
+			// awscfg.WithCustomCABundle would trust the bundle only.
+			pool, err := caCertPool(o.caBundle)
+			if err != nil {
+				return nil, err
+			}
+			client = client.WithTransportOptions(func(t *http.Transport) {
+				if t.TLSClientConfig == nil {
+					t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+				}
+				t.TLSClientConfig.RootCAs = pool
+			})
+		}
+		opts = append(opts, awscfg.WithHTTPClient(client))
+	}
+	return opts, nil
+}
+
+// caCertPool returns the system roots together with the certificates of bundle.
+func caCertPool(bundle []byte) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, errors.New("no certificate found in ca bundle")
+	}
+	return pool, nil
+}
+
+func (o sessionOptions) endpointResolver() aws.EndpointResolverWithOptions {
+	return aws.EndpointResolverWithOptionsFunc(func(service, region string, _ ...interface{}) (aws.Endpoint, error) {
+		u, ok := o.endpoints[service]
+		if !ok {
+			u, ok = o.endpoints[AllServices]
+		}
+		if !ok {
+			return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+		}
+		return aws.Endpoint{
+			URL:               u,
+			HostnameImmutable: true,
+			SigningRegion:     region,
+		}, nil
+	})
+}
+
+func (o sessionOptions) retryer() aws.Retryer {
+	standard := func(so *retry.StandardOptions) {
+		if o.retryMaxAttempts > 0 {
+			so.MaxAttempts = o.retryMaxAttempts
+		}
+		if o.retryMaxBackoff > 0 {
+			so.MaxBackoff = o.retryMaxBackoff
+			so.Backoff = retry.NewExponentialJitterBackoff(o.retryMaxBackoff)
+		}
+	}
+
+	if o.retryMode == aws.RetryModeAdaptive {
+		return retry.NewAdaptiveMode(func(ao *retry.AdaptiveModeOptions) {
+			ao.StandardOptions = append(ao.StandardOptions, standard)
+		})
+	}
+	return retry.NewStandard(standard)
+}
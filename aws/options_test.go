@@ -0,0 +1,130 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// selfSignedCA returns a CA certificate and its PEM encoding.
+func selfSignedCA() (*x509.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "corporate proxy ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	Expect(err).To(BeNil())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).To(BeNil())
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+var _ = Describe("Options", func() {
+	It("should trust the ca bundle in addition to the system roots", func() {
+		cert, bundle := selfSignedCA()
+		pool, err := caCertPool(bundle)
+		Expect(err).To(BeNil())
+		_, err = cert.Verify(x509.VerifyOptions{Roots: pool})
+		Expect(err).To(BeNil())
+
+		if system, err := x509.SystemCertPool(); err == nil {
+			only := x509.NewCertPool()
+			only.AddCert(cert)
+			Expect(pool.Equal(only)).To(BeFalse())
+			Expect(pool.Equal(system)).To(BeFalse())
+		}
+
+		_, err = caCertPool([]byte("not a certificate"))
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("should resolve the service endpoint before the all services one", func() {
+		o := sessionOptions{endpoints: map[string]string{"RDS": "http://localhost:4566"}}
+		_, err := o.endpointResolver().ResolveEndpoint("S3", "us-east-1")
+		var notFound *aws.EndpointNotFoundError
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+
+		o.endpoints[AllServices] = "http://localhost:9000"
+		ep, err := o.endpointResolver().ResolveEndpoint("RDS", "eu-west-1")
+		Expect(err).To(BeNil())
+		Expect(ep).To(Equal(aws.Endpoint{URL: "http://localhost:4566", HostnameImmutable: true, SigningRegion: "eu-west-1"}))
+		ep, err = o.endpointResolver().ResolveEndpoint("S3", "us-east-1")
+		Expect(err).To(BeNil())
+		Expect(ep.URL).To(Equal("http://localhost:9000"))
+	})
+
+	It("should build the retryer of the retry mode", func() {
+		o := sessionOptions{retryMaxAttempts: 7, retryMaxBackoff: 5 * time.Second}
+		Expect(o.retryer()).To(BeAssignableToTypeOf(&retry.Standard{}))
+		Expect(o.retryer().MaxAttempts()).To(Equal(7))
+
+		o.retryMode = aws.RetryModeAdaptive
+		Expect(o.retryer()).To(BeAssignableToTypeOf(&retry.AdaptiveMode{}))
+		Expect(o.retryer().MaxAttempts()).To(Equal(7))
+
+		Expect(sessionOptions{}.retryer().MaxAttempts()).To(Equal(retry.DefaultMaxAttempts))
+	})
+
+	It("should set the timeout and proxy of the http client", func() {
+		sess, err := NewSessions().SetCredential("us-east-1", "AKID", "SECRET").
+			SetTimeout(3 * time.Second).SetProxy("http://proxy.corp:3128").SetRetryMaxAttempts(2).
+			BuildE(context.Background())
+		Expect(err).To(BeNil())
+		cfg := sess["us-east-1"]
+		Expect(cfg.Retryer().MaxAttempts()).To(Equal(2))
+
+		client, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+		Expect(ok).To(BeTrue())
+		Expect(client.GetTimeout()).To(Equal(3 * time.Second))
+		req, err := http.NewRequest(http.MethodGet, "https://rds.us-east-1.amazonaws.com", nil)
+		Expect(err).To(BeNil())
+		proxy, err := client.GetTransport().Proxy(req)
+		Expect(err).To(BeNil())
+		Expect(proxy.String()).To(Equal("http://proxy.corp:3128"))
+	})
+
+	It("should not override the sdk defaults without options", func() {
+		opts, err := sessionOptions{}.loadOptions()
+		Expect(err).To(BeNil())
+		Expect(opts).To(BeEmpty())
+
+		_, err = sessionOptions{caBundleFile: "/nonexistent/ca.pem"}.loadOptions()
+		Expect(err).To(MatchError(ContainSubstring("read ca bundle")))
+	})
+})
@@ -0,0 +1,220 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// SessionKey identifies a session of a Registry.
+type SessionKey struct {
+	Account string
+	Region  string
+}
+
+// Registry holds sessions of several accounts, keyed by account alias and region.
+type Registry struct {
+	sessions map[SessionKey]aws.Config
+}
+
+type accountCreds struct {
+	accounts []account
+	validate bool
+}
+
+type account struct {
+	alias string
+	creds *awsCreds
+
+	// set for accounts reached by assuming a role from another account
+	source  string
+	role    AssumeRole
+	regions []string
+}
+
+func NewRegistry() *accountCreds {
+	return &accountCreds{accounts: []account{}}
+}
+
+// SetAccount adds the sessions built by creds under the account alias.
+func (s *accountCreds) SetAccount(alias string, creds *awsCreds) *accountCreds {
+	s.accounts = append(s.accounts, account{
+		alias: alias,
+		creds: creds,
+	})
+	return s
+}
+
+// SetAssumeRoleAccount adds sessions for regions under the account alias,
+// assuming role with the sessions of the source account. The source account
+// must be set before, it may itself be an assumed role account.
+func (s *accountCreds) SetAssumeRoleAccount(alias, source string, role AssumeRole, regions ...string) *accountCreds {
+	s.accounts = append(s.accounts, account{
+		alias:   alias,
+		source:  source,
+		role:    role,
+		regions: regions,
+	})
+	return s
+}
+
+// SetValidation makes Build call STS GetCallerIdentity with every session.
+func (s *accountCreds) SetValidation(enable bool) *accountCreds {
+	s.validate = enable
+	return s
+}
+
+// Build returns the registry of the sessions which could be loaded, together
+// with a *BuildError listing every account and region that failed.
+func (s *accountCreds) Build(ctx context.Context) (*Registry, error) {
+	reg := &Registry{sessions: map[SessionKey]aws.Config{}}
+	buildErr := &BuildError{}
+
+	for _, acc := range s.accounts {
+		if acc.creds != nil {
+			sess, err := acc.creds.build(ctx, acc.creds.validate || s.validate)
+			for region, cfg := range sess {
+				reg.sessions[SessionKey{Account: acc.alias, Region: region}] = cfg
+			}
+			var be *BuildError
+			if errors.As(err, &be) {
+				for _, re := range be.Errors {
+					re.Account = acc.alias
+					buildErr.Errors = append(buildErr.Errors, re)
+				}
+			}
+			continue
+		}
+
+		for _, region := range acc.regions {
+			cfg, err := reg.assumeRole(acc, region)
+			if err == nil && s.validate {
+				err = validateSession(ctx, cfg)
+			}
+			if err != nil {
+				buildErr.Errors = append(buildErr.Errors, &RegionError{Account: acc.alias, Region: region, Err: err})
+				continue
+			}
+			reg.sessions[SessionKey{Account: acc.alias, Region: region}] = cfg
+		}
+	}
+
+	if len(buildErr.Errors) > 0 {
+		return reg, buildErr
+	}
+	return reg, nil
+}
+
+// assumeRole chains role on top of the source account session of region,
+// or of any region of the source account when region is not available.
+func (r *Registry) assumeRole(acc account, region string) (aws.Config, error) {
+	base, ok := r.Get(acc.source, region)
+	if !ok {
+		regions := r.Regions(acc.source)
+		if len(regions) == 0 {
+			return aws.Config{}, fmt.Errorf("source account %s has no session", acc.source)
+		}
+		base, _ = r.Get(acc.source, regions[0])
+	}
+
+	cfg := base.Copy()
+	cfg.Region = region
+	cfg.Credentials = aws.NewCredentialsCache(newAssumeRoleProvider(base, acc.role))
+	return cfg, nil
+}
+
+// Get returns the session of account in region.
+func (r *Registry) Get(account, region string) (aws.Config, bool) {
+	cfg, ok := r.sessions[SessionKey{Account: account, Region: region}]
+	return cfg, ok
+}
+
+// Account returns the sessions of account keyed by region.
+func (r *Registry) Account(account string) Sessions {
+	sess := Sessions{}
+	for k, cfg := range r.sessions {
+		if k.Account == account {
+			sess[k.Region] = cfg
+		}
+	}
+	return sess
+}
+
+// Region returns the sessions of region keyed by account.
+func (r *Registry) Region(region string) map[string]aws.Config {
+	sess := map[string]aws.Config{}
+	for k, cfg := range r.sessions {
+		if k.Region == region {
+			sess[k.Account] = cfg
+		}
+	}
+	return sess
+}
+
+// Accounts returns the sorted account aliases.
+func (r *Registry) Accounts() []string {
+	seen := map[string]bool{}
+	var accounts []string
+	for k := range r.sessions {
+		if !seen[k.Account] {
+			seen[k.Account] = true
+			accounts = append(accounts, k.Account)
+		}
+	}
+	sort.Strings(accounts)
+	return accounts
+}
+
+// Regions returns the sorted regions of account.
+func (r *Registry) Regions(account string) []string {
+	var regions []string
+	for k := range r.sessions {
+		if k.Account == account {
+			regions = append(regions, k.Region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Keys returns every account and region pair, sorted by account then region.
+func (r *Registry) Keys() []SessionKey {
+	keys := make([]SessionKey, 0, len(r.sessions))
+	for k := range r.sessions {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Account != keys[j].Account {
+			return keys[i].Account < keys[j].Account
+		}
+		return keys[i].Region < keys[j].Region
+	})
+	return keys
+}
+
+// Range calls fn for every session in Keys order, stopping at the first error.
+func (r *Registry) Range(fn func(key SessionKey, cfg aws.Config) error) error {
+	for _, k := range r.Keys() {
+		if err := fn(k, r.sessions[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
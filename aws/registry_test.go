@@ -0,0 +1,119 @@
+// Copyright 2023 SphereEx Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// stsServer answers GetCallerIdentity, counting the calls.
+func stsServer(calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<GetCallerIdentityResponse><GetCallerIdentityResult>` +
+			`<Arn>arn:aws:iam::123456789012:user/dba</Arn><UserId>AIDA</UserId><Account>123456789012</Account>` +
+			`</GetCallerIdentityResult></GetCallerIdentityResponse>`))
+	}))
+}
+
+var _ = Describe("Registry", func() {
+	It("should validate the account sessions without changing their creds", func() {
+		var calls int32
+		server := stsServer(&calls)
+		defer server.Close()
+
+		creds := NewSessions().SetCredential("us-east-1", "AKID", "SECRET").SetEndpoint(AllServices, server.URL)
+		reg, err := NewRegistry().SetAccount("prod", creds).SetValidation(true).Build(context.Background())
+		Expect(err).To(BeNil())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+		_, ok := reg.Get("prod", "us-east-1")
+		Expect(ok).To(BeTrue())
+
+		Expect(creds.validate).To(BeFalse())
+		_, err = creds.BuildE(context.Background())
+		Expect(err).To(BeNil())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+	})
+
+	It("should look up the sessions by account and region", func() {
+		reg, err := NewRegistry().
+			SetAccount("staging", NewSessions().SetCredential("us-east-1", "AKID", "SECRET")).
+			SetAccount("prod", NewSessions().
+				SetCredential("us-east-1", "AKID", "SECRET").
+				SetCredential("eu-west-1", "AKID", "SECRET")).
+			Build(context.Background())
+		Expect(err).To(BeNil())
+
+		cfg, ok := reg.Get("prod", "eu-west-1")
+		Expect(ok).To(BeTrue())
+		Expect(cfg.Region).To(Equal("eu-west-1"))
+		_, ok = reg.Get("staging", "eu-west-1")
+		Expect(ok).To(BeFalse())
+
+		Expect(reg.Accounts()).To(Equal([]string{"prod", "staging"}))
+		Expect(reg.Regions("prod")).To(Equal([]string{"eu-west-1", "us-east-1"}))
+		Expect(reg.Account("prod")).To(HaveLen(2))
+		Expect(reg.Region("us-east-1")).To(HaveKey("staging"))
+		Expect(reg.Keys()).To(Equal([]SessionKey{
+			{Account: "prod", Region: "eu-west-1"},
+			{Account: "prod", Region: "us-east-1"},
+			{Account: "staging", Region: "us-east-1"},
+		}))
+
+		var keys []SessionKey
+		stop := errors.New("stop")
+		err = reg.Range(func(key SessionKey, cfg aws.Config) error {
+			Expect(cfg.Region).To(Equal(key.Region))
+			keys = append(keys, key)
+			if len(keys) == 2 {
+				return stop
+			}
+			return nil
+		})
+		Expect(err).To(Equal(stop))
+		Expect(keys).To(Equal(reg.Keys()[:2]))
+	})
+
+	It("should assume roles from the source account and report failed accounts", func() {
+		isolateEnv()
+		creds := NewSessions().SetCredential("us-east-1", "AKID", "SECRET").SetEnvCredential("eu-west-1")
+		reg, err := NewRegistry().
+			SetAccount("hub", creds).
+			SetAssumeRoleAccount("spoke", "hub", AssumeRole{RoleArn: "arn:aws:iam::210987654321:role/dba"}, "us-east-1", "ap-south-1").
+			SetAssumeRoleAccount("orphan", "missing", AssumeRole{RoleArn: "arn:aws:iam::210987654321:role/dba"}, "us-east-1").
+			Build(context.Background())
+		Expect(reg.Regions("spoke")).To(Equal([]string{"ap-south-1", "us-east-1"}))
+		cfg, _ := reg.Get("spoke", "ap-south-1")
+		Expect(cfg.Region).To(Equal("ap-south-1"))
+
+		var buildErr *BuildError
+		Expect(errors.As(err, &buildErr)).To(BeTrue())
+		Expect(buildErr.Errors).To(HaveLen(2))
+		Expect(buildErr.Errors[0].Account).To(Equal("hub"))
+		Expect(buildErr.Errors[0].Region).To(Equal("eu-west-1"))
+		Expect(buildErr.Errors[1].Account).To(Equal("orphan"))
+		Expect(buildErr.Errors[1].Error()).To(Equal("account orphan region us-east-1: source account missing has no session"))
+	})
+})